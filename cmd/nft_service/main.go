@@ -12,6 +12,7 @@ import "nft_service/internal/application"
 // @LicenseName MIT
 // @LicenseURL https://en.wikipedia.org/wiki/MIT_License
 // @Server http://127.0.0.1:8008 Server-1
+// @SecurityScheme AdminToken http bearer ADMIN_TOKEN of the service
 func main() {
 	application.StartApp()
 }
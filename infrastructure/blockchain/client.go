@@ -0,0 +1,274 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoSubscriptionProvider is returned by subscriptions when none of the providers is a websocket endpoint
+var ErrNoSubscriptionProvider = errors.New("no websocket rpc provider configured")
+
+// Client is an ethereum JSON-RPC client spread over several providers.
+// Calls go to the first healthy provider and fail over to the next one on provider errors.
+type Client struct {
+	providers   []*provider
+	maxBlockLag uint64
+}
+
+type provider struct {
+	name        string
+	url         string
+	client      *ethclient.Client
+	healthy     bool
+	blockNumber uint64
+	mu          sync.RWMutex
+}
+
+// NewClient dials every endpoint. A provider is considered stale when its head is more
+// than maxBlockLag blocks behind the highest head seen across providers.
+func NewClient(urls []string, maxBlockLag uint64) (*Client, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no rpc urls configured")
+	}
+
+	var (
+		l         = slog.Default()
+		c         = &Client{maxBlockLag: maxBlockLag}
+		connected int
+		names     = make(map[string]int)
+	)
+
+	for _, rawURL := range urls {
+		p := &provider{
+			name: providerName(rawURL, names),
+			url:  rawURL,
+		}
+
+		client, err := ethclient.Dial(rawURL)
+		if err != nil {
+			l.Error("failed to dial rpc provider", slog.String("provider", p.name), slog.Any("error", err))
+		} else {
+			p.client = client
+			p.healthy = true
+			connected++
+		}
+
+		providerHealthy.WithLabelValues(p.name).Set(boolToFloat(p.healthy))
+		c.providers = append(c.providers, p)
+	}
+
+	if connected == 0 {
+		return nil, errors.New("failed to dial any rpc provider")
+	}
+
+	return c, nil
+}
+
+// StartHealthCheck periodically polls the head of every provider and marks failing or stale ones unhealthy
+func (c *Client) StartHealthCheck(ctx context.Context, interval time.Duration) {
+	l := slog.Default()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.checkProviders(ctx)
+		case <-ctx.Done():
+			l.Info("rpc health check stopped")
+			return
+		}
+	}
+}
+
+func (c *Client) checkProviders(ctx context.Context) {
+	var (
+		l       = slog.Default()
+		heads   = make([]uint64, len(c.providers))
+		errs    = make([]error, len(c.providers))
+		maxHead uint64
+		wg      sync.WaitGroup
+	)
+
+	for i, p := range c.providers {
+		wg.Add(1)
+		go func(i int, p *provider) {
+			defer wg.Done()
+			heads[i], errs[i] = p.head(ctx)
+		}(i, p)
+	}
+	wg.Wait()
+
+	for _, head := range heads {
+		if head > maxHead {
+			maxHead = head
+		}
+	}
+
+	for i, p := range c.providers {
+		healthy := errs[i] == nil && heads[i]+c.maxBlockLag >= maxHead
+
+		p.mu.Lock()
+		wasHealthy := p.healthy
+		p.healthy = healthy
+		if errs[i] == nil {
+			p.blockNumber = heads[i]
+		}
+		p.mu.Unlock()
+
+		providerHealthy.WithLabelValues(p.name).Set(boolToFloat(healthy))
+		providerBlockNumber.WithLabelValues(p.name).Set(float64(heads[i]))
+
+		if wasHealthy != healthy {
+			l.Warn("rpc provider health changed",
+				slog.String("provider", p.name),
+				slog.Bool("healthy", healthy),
+				slog.Uint64("block_number", heads[i]),
+				slog.Uint64("max_block_number", maxHead),
+				slog.Any("error", errs[i]),
+			)
+		}
+	}
+}
+
+// head returns the latest block number of the provider, redialing it if it was never connected
+func (p *provider) head(ctx context.Context) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	client := p.ethClient()
+	if client == nil {
+		dialed, err := ethclient.DialContext(ctx, p.url)
+		if err != nil {
+			return 0, err
+		}
+		p.mu.Lock()
+		p.client = dialed
+		p.mu.Unlock()
+		client = dialed
+	}
+
+	return client.BlockNumber(ctx)
+}
+
+func (p *provider) ethClient() *ethclient.Client {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.client
+}
+
+func (p *provider) markUnhealthy() {
+	p.mu.Lock()
+	p.healthy = false
+	p.mu.Unlock()
+	providerHealthy.WithLabelValues(p.name).Set(0)
+}
+
+// ordered returns the healthy providers first, unhealthy ones are kept as a last resort
+func (c *Client) ordered() []*provider {
+	healthy := make([]*provider, 0, len(c.providers))
+	var unhealthy []*provider
+
+	for _, p := range c.providers {
+		p.mu.RLock()
+		ok := p.healthy && p.client != nil
+		connected := p.client != nil
+		p.mu.RUnlock()
+
+		switch {
+		case ok:
+			healthy = append(healthy, p)
+		case connected:
+			unhealthy = append(unhealthy, p)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// call runs fn against the providers in order until one of them answers without a provider error
+func call[T any](c *Client, method string, fn func(client *ethclient.Client) (T, error)) (T, error) {
+	var (
+		l       = slog.Default()
+		zero    T
+		lastErr = errors.New("no rpc provider available")
+	)
+
+	for attempt, p := range c.ordered() {
+		start := time.Now()
+		result, err := fn(p.ethClient())
+		observeRequest(p.name, method, start, err)
+
+		if err == nil || !isProviderError(err) {
+			l.Debug("rpc call",
+				slog.String("provider", p.name),
+				slog.String("method", method),
+				slog.Int("attempt", attempt+1),
+			)
+			return result, err
+		}
+
+		l.Warn("rpc call failed, failing over",
+			slog.String("provider", p.name),
+			slog.String("method", method),
+			slog.Any("error", err),
+		)
+		p.markUnhealthy()
+		lastErr = err
+	}
+
+	return zero, fmt.Errorf("%s: %w", method, lastErr)
+}
+
+// isProviderError reports whether the error is caused by the provider itself (transport, rate limit, server error)
+// rather than by the request, so another provider may answer it
+func isProviderError(err error) bool {
+	if errors.Is(err, ethereum.NotFound) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		// -32005 limit exceeded, -32603 internal error
+		return rpcErr.ErrorCode() == -32005 || rpcErr.ErrorCode() == -32603
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		return false
+	}
+
+	return true
+}
+
+// providerName is the host of the endpoint, so API keys in the path never reach logs and metrics
+func providerName(rawURL string, names map[string]int) string {
+	name := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		name = u.Host
+	}
+	name = strings.ToLower(name)
+
+	names[name]++
+	if names[name] > 1 {
+		name = fmt.Sprintf("%s#%d", name, names[name])
+	}
+
+	return name
+}
+
+func boolToFloat(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
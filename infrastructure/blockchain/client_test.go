@@ -0,0 +1,74 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeEthService struct {
+	head uint64
+}
+
+func (s *fakeEthService) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(s.head)
+}
+
+func newFakeNode(t *testing.T, head uint64) *httptest.Server {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", &fakeEthService{head: head}))
+	return httptest.NewServer(server)
+}
+
+func TestClient_FailsOverOnProviderError(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+
+	node := newFakeNode(t, 100)
+	defer node.Close()
+
+	client, err := NewClient([]string{broken.URL, node.URL}, 5)
+	assert.NoError(t, err)
+
+	head, err := client.BlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), head)
+
+	assert.False(t, client.providers[0].healthy)
+	assert.Equal(t, node.URL[len("http://"):], client.ordered()[0].name)
+}
+
+func TestClient_MarksStaleProviderUnhealthy(t *testing.T) {
+	stale := newFakeNode(t, 90)
+	defer stale.Close()
+
+	node := newFakeNode(t, 100)
+	defer node.Close()
+
+	client, err := NewClient([]string{stale.URL, node.URL}, 5)
+	assert.NoError(t, err)
+
+	client.checkProviders(context.Background())
+
+	assert.False(t, client.providers[0].healthy)
+	assert.True(t, client.providers[1].healthy)
+}
+
+func TestIsProviderError(t *testing.T) {
+	assert.False(t, isProviderError(ethereum.NotFound))
+	assert.False(t, isProviderError(context.Canceled))
+	assert.True(t, isProviderError(rpc.HTTPError{StatusCode: http.StatusTooManyRequests}))
+}
+
+func TestProviderName(t *testing.T) {
+	names := make(map[string]int)
+	assert.Equal(t, "sepolia.infura.io", providerName("https://sepolia.infura.io/v3/secret", names))
+	assert.Equal(t, "sepolia.infura.io#2", providerName("https://sepolia.infura.io/v3/other", names))
+}
@@ -0,0 +1,36 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+)
+
+// Finality is when a block is considered final: either Depth blocks below the head
+// or, when Finalized is set, at or below the block tagged "finalized" by the node
+type Finality struct {
+	Depth     uint64
+	Finalized bool
+}
+
+// SafeHead returns the highest block number that is final
+func (c *Client) SafeHead(ctx context.Context, finality Finality) (uint64, error) {
+	if finality.Finalized {
+		header, err := c.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get finalized block: %w", err)
+		}
+		return header.Number.Uint64(), nil
+	}
+
+	head, err := c.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block number: %w", err)
+	}
+
+	if head < finality.Depth {
+		return 0, nil
+	}
+	return head - finality.Depth, nil
+}
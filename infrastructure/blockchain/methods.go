@@ -0,0 +1,152 @@
+package blockchain
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// the client backs the generated contract bindings
+var _ bind.ContractBackend = (*Client)(nil)
+
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	return call(c, "eth_chainId", func(client *ethclient.Client) (*big.Int, error) {
+		return client.ChainID(ctx)
+	})
+}
+
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	return call(c, "eth_blockNumber", func(client *ethclient.Client) (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return call(c, "eth_getBlockByNumber", func(client *ethclient.Client) (*types.Header, error) {
+		return client.HeaderByNumber(ctx, number)
+	})
+}
+
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return call(c, "eth_getTransactionCount", func(client *ethclient.Client) (uint64, error) {
+		return client.NonceAt(ctx, account, blockNumber)
+	})
+}
+
+func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return call(c, "eth_getTransactionCount", func(client *ethclient.Client) (uint64, error) {
+		return client.PendingNonceAt(ctx, account)
+	})
+}
+
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return call(c, "eth_getBalance", func(client *ethclient.Client) (*big.Int, error) {
+		return client.BalanceAt(ctx, account, blockNumber)
+	})
+}
+
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return call(c, "eth_gasPrice", func(client *ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasPrice(ctx)
+	})
+}
+
+func (c *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return call(c, "eth_maxPriorityFeePerGas", func(client *ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasTipCap(ctx)
+	})
+}
+
+func (c *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return call(c, "eth_estimateGas", func(client *ethclient.Client) (uint64, error) {
+		return client.EstimateGas(ctx, msg)
+	})
+}
+
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return call(c, "eth_call", func(client *ethclient.Client) ([]byte, error) {
+		return client.CallContract(ctx, msg, blockNumber)
+	})
+}
+
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return call(c, "eth_getCode", func(client *ethclient.Client) ([]byte, error) {
+		return client.CodeAt(ctx, account, blockNumber)
+	})
+}
+
+func (c *Client) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return call(c, "eth_getCode", func(client *ethclient.Client) ([]byte, error) {
+		return client.PendingCodeAt(ctx, account)
+	})
+}
+
+// PendingCallContract executes the call against the pending block, so it sees the transactions already in the mempool
+func (c *Client) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	return call(c, "eth_call", func(client *ethclient.Client) ([]byte, error) {
+		return client.PendingCallContract(ctx, msg)
+	})
+}
+
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return call(c, "eth_getTransactionReceipt", func(client *ethclient.Client) (*types.Receipt, error) {
+		return client.TransactionReceipt(ctx, txHash)
+	})
+}
+
+func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return call(c, "eth_getLogs", func(client *ethclient.Client) ([]types.Log, error) {
+		return client.FilterLogs(ctx, query)
+	})
+}
+
+// SendTransaction broadcasts the transaction. A fallback provider answering "already known" means
+// an earlier provider did deliver the transaction before failing, so it is treated as success.
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	attempt := 0
+	_, err := call(c, "eth_sendRawTransaction", func(client *ethclient.Client) (struct{}, error) {
+		attempt++
+		err := client.SendTransaction(ctx, tx)
+		if err != nil && attempt > 1 && strings.Contains(strings.ToLower(err.Error()), "already known") {
+			return struct{}{}, nil
+		}
+		return struct{}{}, err
+	})
+	return err
+}
+
+// SubscribeFilterLogs subscribes to logs through the first healthy websocket provider.
+// ErrNoSubscriptionProvider is returned when no websocket endpoint is configured.
+func (c *Client) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	lastErr := ErrNoSubscriptionProvider
+
+	for _, p := range c.ordered() {
+		if !isWebsocket(p.url) {
+			continue
+		}
+
+		start := time.Now()
+		sub, err := p.ethClient().SubscribeFilterLogs(ctx, query, ch)
+		observeRequest(p.name, "eth_subscribe", start, err)
+		if err == nil {
+			slog.Default().Info("subscribed to logs", slog.String("provider", p.name))
+			return sub, nil
+		}
+
+		p.markUnhealthy()
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+func isWebsocket(rawURL string) bool {
+	return strings.HasPrefix(rawURL, "ws://") || strings.HasPrefix(rawURL, "wss://")
+}
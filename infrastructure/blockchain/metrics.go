@@ -0,0 +1,39 @@
+package blockchain
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"time"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rpc_requests_total",
+		Help: "Ethereum JSON-RPC calls by provider, method and result.",
+	}, []string{"provider", "method", "status"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rpc_request_duration_seconds",
+		Help:    "Ethereum JSON-RPC call latency by provider and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider", "method"})
+
+	providerHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rpc_provider_healthy",
+		Help: "Whether the ethereum JSON-RPC provider is healthy (1) or not (0).",
+	}, []string{"provider"})
+
+	providerBlockNumber = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rpc_provider_block_number",
+		Help: "Latest block number reported by the ethereum JSON-RPC provider.",
+	}, []string{"provider"})
+)
+
+func observeRequest(provider, method string, start time.Time, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	requestsTotal.WithLabelValues(provider, method, status).Inc()
+	requestDuration.WithLabelValues(provider, method).Observe(time.Since(start).Seconds())
+}
@@ -2,25 +2,78 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"math/big"
+	"nft_service/infrastructure/blockchain"
+	"nft_service/infrastructure/messaging"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Host                string
-	Port                string
-	DBURI               string
-	AMQPURI             string
-	CacheUpdateInterval time.Duration
-	UserAddress         string
-	UserPrivateKey      string
-	NetworkName         string
-	InfuraApiKey        string
-	ChainID             int64
-	ContractAddress     string
-	ContractABIPath     string
+	Host                   string
+	Port                   string
+	DBURI                  string
+	QueueBackend           string // rabbitmq, postgres or memory
+	AMQPURI                string
+	CacheUpdateInterval    time.Duration
+	ViewCacheTTL           time.Duration
+	UserAddress            string
+	UserPrivateKey         string
+	SignerType             string
+	KeystorePath           string
+	KeystorePasswordFile   string
+	RemoteSignerURL        string
+	NetworkName            string
+	InfuraApiKey           string
+	RPCMaxBlockLag         uint64
+	RPCHealthCheckInterval time.Duration
+	Chains                 []ChainConfig // the primary chain of CHAIN_ID first
+	ContractAddress        string
+	ContractABIPath        string
+	TxMonitorInterval      time.Duration
+	BalanceCheckInterval   time.Duration
+	LowBalanceWebhookURL   string
+	OutboxRelayInterval    time.Duration
+	EventsExchange         string // topic exchange of the domain events
+	ReceiptRetry           messaging.RetryPolicy
+	TxStuckAfter           time.Duration
+	TxMaxSpeedUps          int
+	IndexerBlockRange      uint64
+	IndexerPollInterval    time.Duration
+	BatchMaxItems          int
+	BatchChunkSize         int
+	AdminToken             string
+	MinterRole             string
+}
+
+// ChainConfig is the configuration of a chain the service sends transactions to
+type ChainConfig struct {
+	ChainID            int64
+	RPCURLs            []string // empty for the primary chain means Infura
+	Finality           blockchain.Finality
+	GasLimitMultiplier float64
+	MaxFeePerGas       *big.Int
+	LowBalance         *big.Int // wallet balance in wei below which the low balance alert fires
+	IndexerStartBlock  *uint64  // first block to index when there is no cursor yet, nil means the current head
+}
+
+// Chain returns the configuration of the chain
+func (c *Config) Chain(chainID int64) (ChainConfig, bool) {
+	for _, chain := range c.Chains {
+		if chain.ChainID == chainID {
+			return chain, true
+		}
+	}
+	return ChainConfig{}, false
+}
+
+// PrimaryChain returns the configuration of the chain of CHAIN_ID, the chain of the default collection
+func (c *Config) PrimaryChain() ChainConfig {
+	return c.Chains[0]
 }
 
 func LoadConfig() (*Config, error) {
@@ -45,10 +98,22 @@ func LoadConfig() (*Config, error) {
 		return nil, errors.New("DB_URI is not set")
 	}
 
+	queueBackend := os.Getenv("QUEUE_BACKEND")
+	if queueBackend == "" {
+		queueBackend = "rabbitmq"
+	}
+
 	amqpURI := os.Getenv("AMQP_URI")
-	if amqpURI == "" {
-		l.Error("AMQP_URI is not set")
-		return nil, errors.New("AMQP_URI is not set")
+	switch queueBackend {
+	case "rabbitmq":
+		if amqpURI == "" {
+			l.Error("AMQP_URI is not set")
+			return nil, errors.New("AMQP_URI is not set")
+		}
+	case "postgres", "memory":
+	default:
+		l.Error("QUEUE_BACKEND is invalid", "queue_backend", queueBackend)
+		return nil, errors.New("QUEUE_BACKEND must be one of rabbitmq, postgres, memory")
 	}
 
 	cacheUpdateInterval := os.Getenv("CACHE_UPDATE_INTERVAL")
@@ -63,28 +128,54 @@ func LoadConfig() (*Config, error) {
 		return nil, errors.New("CACHE_UPDATE_INTERVAL is not integer")
 	}
 
+	viewCacheTTL := int64(60)
+	if v := os.Getenv("VIEW_CACHE_TTL"); v != "" {
+		viewCacheTTL, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || viewCacheTTL < 0 {
+			l.Error("VIEW_CACHE_TTL is not non-negative integer", "error", err)
+			return nil, errors.New("VIEW_CACHE_TTL is not non-negative integer")
+		}
+	}
+
 	userAddress := os.Getenv("USER_ADDRESS")
 	if userAddress == "" {
 		l.Error("USER_ADDRESS is not set")
 		return nil, errors.New("USER_ADDRESS is not set")
 	}
 
-	userPrivateKey := os.Getenv("USER_PRIVATE_KEY")
-	if userPrivateKey == "" {
-		l.Error("USER_PRIVATE_KEY is not set")
-		return nil, errors.New("USER_PRIVATE_KEY is not set")
+	signerType := os.Getenv("SIGNER_TYPE")
+	if signerType == "" {
+		signerType = "raw"
 	}
 
-	networkName := os.Getenv("NETWORK_NAME")
-	if networkName == "" {
-		l.Error("NETWORK_NAME is not set")
-		return nil, errors.New("NETWORK_NAME is not set")
-	}
+	userPrivateKey := os.Getenv("USER_PRIVATE_KEY")
+	keystorePath := os.Getenv("KEYSTORE_PATH")
+	keystorePasswordFile := os.Getenv("KEYSTORE_PASSWORD_FILE")
+	remoteSignerURL := os.Getenv("REMOTE_SIGNER_URL")
 
-	infuraApiKey := os.Getenv("INFURA_API_KEY")
-	if infuraApiKey == "" {
-		l.Error("INFURA_API_KEY is not set")
-		return nil, errors.New("INFURA_API_KEY is not set")
+	switch signerType {
+	case "raw":
+		if userPrivateKey == "" {
+			l.Error("USER_PRIVATE_KEY is not set")
+			return nil, errors.New("USER_PRIVATE_KEY is not set")
+		}
+		if os.Getenv("GIN_MODE") == "release" {
+			l.Error("SIGNER_TYPE raw is not allowed in release mode")
+			return nil, errors.New("SIGNER_TYPE raw is not allowed in release mode")
+		}
+	case "keystore":
+		if keystorePath == "" || keystorePasswordFile == "" {
+			l.Error("KEYSTORE_PATH or KEYSTORE_PASSWORD_FILE is not set")
+			return nil, errors.New("KEYSTORE_PATH or KEYSTORE_PASSWORD_FILE is not set")
+		}
+	case "remote":
+		if remoteSignerURL == "" {
+			l.Error("REMOTE_SIGNER_URL is not set")
+			return nil, errors.New("REMOTE_SIGNER_URL is not set")
+		}
+	default:
+		l.Error("SIGNER_TYPE is invalid", "signer_type", signerType)
+		return nil, errors.New("SIGNER_TYPE must be one of raw, keystore, remote")
 	}
 
 	chainID := os.Getenv("CHAIN_ID")
@@ -99,29 +190,313 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	primaryChain, err := loadChain("", intChainID, ChainConfig{
+		Finality:           blockchain.Finality{Depth: 12},
+		GasLimitMultiplier: 1.2,
+		MaxFeePerGas:       new(big.Int).Mul(big.NewInt(200), big.NewInt(1e9)),
+		LowBalance:         new(big.Int).Mul(big.NewInt(50_000_000), big.NewInt(1e9)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	chains := []ChainConfig{primaryChain}
+
+	// every extra chain is configured by CHAIN_<id>_ prefixed variables, unset ones fall back to the primary chain
+	for _, v := range strings.Split(os.Getenv("EXTRA_CHAIN_IDS"), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+
+		extraChainID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || extraChainID <= 0 {
+			l.Error("EXTRA_CHAIN_IDS is not a list of positive integers", "error", err)
+			return nil, errors.New("EXTRA_CHAIN_IDS is not a list of positive integers")
+		}
+
+		for _, chain := range chains {
+			if chain.ChainID == extraChainID {
+				l.Error("EXTRA_CHAIN_IDS contains a duplicate chain", "chain_id", extraChainID)
+				return nil, errors.New("EXTRA_CHAIN_IDS contains a duplicate chain")
+			}
+		}
+
+		prefix := fmt.Sprintf("CHAIN_%d_", extraChainID)
+		chain, err := loadChain(prefix, extraChainID, primaryChain)
+		if err != nil {
+			return nil, err
+		}
+		if len(chain.RPCURLs) == 0 {
+			l.Error(prefix + "RPC_URLS is not set")
+			return nil, errors.New(prefix + "RPC_URLS is not set")
+		}
+
+		chains = append(chains, chain)
+	}
+
+	// NETWORK_NAME and INFURA_API_KEY are only needed to build the Infura URL when RPC_URLS is not set
+	networkName := os.Getenv("NETWORK_NAME")
+	if networkName == "" && len(primaryChain.RPCURLs) == 0 {
+		l.Error("NETWORK_NAME is not set")
+		return nil, errors.New("NETWORK_NAME is not set")
+	}
+
+	infuraApiKey := os.Getenv("INFURA_API_KEY")
+	if infuraApiKey == "" && len(primaryChain.RPCURLs) == 0 {
+		l.Error("INFURA_API_KEY is not set")
+		return nil, errors.New("INFURA_API_KEY is not set")
+	}
+
+	rpcMaxBlockLag := uint64(5)
+	if v := os.Getenv("RPC_MAX_BLOCK_LAG"); v != "" {
+		rpcMaxBlockLag, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			l.Error("RPC_MAX_BLOCK_LAG is not non-negative integer", "error", err)
+			return nil, errors.New("RPC_MAX_BLOCK_LAG is not non-negative integer")
+		}
+	}
+
+	rpcHealthCheckInterval := int64(15)
+	if v := os.Getenv("RPC_HEALTH_CHECK_INTERVAL"); v != "" {
+		rpcHealthCheckInterval, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || rpcHealthCheckInterval <= 0 {
+			l.Error("RPC_HEALTH_CHECK_INTERVAL is not positive integer", "error", err)
+			return nil, errors.New("RPC_HEALTH_CHECK_INTERVAL is not positive integer")
+		}
+	}
+
 	contractAddress := os.Getenv("CONTRACT_ADDRESS")
 	if contractAddress == "" {
 		l.Error("CONTRACT_ADDRESS is not set")
 		return nil, errors.New("CONTRACT_ADDRESS is not set")
 	}
+	// optional, the ABI is compared against the generated bindings at startup
 	contractABIPath := os.Getenv("CONTRACT_ABI_PATH")
-	if contractABIPath == "" {
-		l.Error("CONTRACT_ABI_PATH is not set")
-		return nil, errors.New("CONTRACT_ABI_PATH is not set")
+
+	txMonitorInterval := int64(30)
+	if v := os.Getenv("TX_MONITOR_INTERVAL"); v != "" {
+		txMonitorInterval, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || txMonitorInterval <= 0 {
+			l.Error("TX_MONITOR_INTERVAL is not positive integer", "error", err)
+			return nil, errors.New("TX_MONITOR_INTERVAL is not positive integer")
+		}
+	}
+
+	balanceCheckInterval := int64(60)
+	if v := os.Getenv("BALANCE_CHECK_INTERVAL"); v != "" {
+		balanceCheckInterval, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || balanceCheckInterval <= 0 {
+			l.Error("BALANCE_CHECK_INTERVAL is not positive integer", "error", err)
+			return nil, errors.New("BALANCE_CHECK_INTERVAL is not positive integer")
+		}
+	}
+
+	eventsExchange := os.Getenv("EVENTS_EXCHANGE")
+	if eventsExchange == "" {
+		eventsExchange = "nft_service.events"
+	}
+
+	outboxRelayInterval := int64(1)
+	if v := os.Getenv("OUTBOX_RELAY_INTERVAL"); v != "" {
+		outboxRelayInterval, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || outboxRelayInterval <= 0 {
+			l.Error("OUTBOX_RELAY_INTERVAL is not positive integer", "error", err)
+			return nil, errors.New("OUTBOX_RELAY_INTERVAL is not positive integer")
+		}
+	}
+
+	receiptRetry := messaging.RetryPolicy{MaxAttempts: 40, BaseDelay: 5 * time.Second, MaxDelay: 5 * time.Minute}
+	if v := os.Getenv("RECEIPT_MAX_ATTEMPTS"); v != "" {
+		receiptRetry.MaxAttempts, err = strconv.Atoi(v)
+		if err != nil || receiptRetry.MaxAttempts <= 0 {
+			l.Error("RECEIPT_MAX_ATTEMPTS is not positive integer", "error", err)
+			return nil, errors.New("RECEIPT_MAX_ATTEMPTS is not positive integer")
+		}
+	}
+	if v := os.Getenv("RECEIPT_RETRY_BASE_DELAY"); v != "" {
+		baseDelay, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || baseDelay <= 0 {
+			l.Error("RECEIPT_RETRY_BASE_DELAY is not positive integer", "error", err)
+			return nil, errors.New("RECEIPT_RETRY_BASE_DELAY is not positive integer")
+		}
+		receiptRetry.BaseDelay = time.Duration(baseDelay) * time.Second
+	}
+	if v := os.Getenv("RECEIPT_RETRY_MAX_DELAY"); v != "" {
+		maxDelay, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || maxDelay <= 0 {
+			l.Error("RECEIPT_RETRY_MAX_DELAY is not positive integer", "error", err)
+			return nil, errors.New("RECEIPT_RETRY_MAX_DELAY is not positive integer")
+		}
+		receiptRetry.MaxDelay = time.Duration(maxDelay) * time.Second
+	}
+	if receiptRetry.MaxDelay < receiptRetry.BaseDelay {
+		l.Error("RECEIPT_RETRY_MAX_DELAY is less than RECEIPT_RETRY_BASE_DELAY")
+		return nil, errors.New("RECEIPT_RETRY_MAX_DELAY is less than RECEIPT_RETRY_BASE_DELAY")
+	}
+
+	txStuckAfter := int64(180)
+	if v := os.Getenv("TX_STUCK_AFTER"); v != "" {
+		txStuckAfter, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || txStuckAfter <= 0 {
+			l.Error("TX_STUCK_AFTER is not positive integer", "error", err)
+			return nil, errors.New("TX_STUCK_AFTER is not positive integer")
+		}
+	}
+
+	txMaxSpeedUps := 3
+	if v := os.Getenv("TX_MAX_SPEED_UPS"); v != "" {
+		txMaxSpeedUps, err = strconv.Atoi(v)
+		if err != nil || txMaxSpeedUps < 0 {
+			l.Error("TX_MAX_SPEED_UPS is not non-negative integer", "error", err)
+			return nil, errors.New("TX_MAX_SPEED_UPS is not non-negative integer")
+		}
+	}
+
+	minterRole := os.Getenv("MINTER_ROLE")
+	if minterRole == "" {
+		minterRole = "MINTER_ROLE"
+	}
+
+	batchMaxItems := 500
+	if v := os.Getenv("BATCH_MAX_ITEMS"); v != "" {
+		batchMaxItems, err = strconv.Atoi(v)
+		if err != nil || batchMaxItems < 1 {
+			l.Error("BATCH_MAX_ITEMS is not positive integer", "error", err)
+			return nil, errors.New("BATCH_MAX_ITEMS is not positive integer")
+		}
+	}
+
+	batchChunkSize := 16
+	if v := os.Getenv("BATCH_CHUNK_SIZE"); v != "" {
+		batchChunkSize, err = strconv.Atoi(v)
+		if err != nil || batchChunkSize < 1 {
+			l.Error("BATCH_CHUNK_SIZE is not positive integer", "error", err)
+			return nil, errors.New("BATCH_CHUNK_SIZE is not positive integer")
+		}
+	}
+
+	indexerBlockRange := uint64(1000)
+	if v := os.Getenv("INDEXER_BLOCK_RANGE"); v != "" {
+		indexerBlockRange, err = strconv.ParseUint(v, 10, 64)
+		if err != nil || indexerBlockRange == 0 {
+			l.Error("INDEXER_BLOCK_RANGE is not positive integer", "error", err)
+			return nil, errors.New("INDEXER_BLOCK_RANGE is not positive integer")
+		}
+	}
+
+	indexerPollInterval := int64(15)
+	if v := os.Getenv("INDEXER_POLL_INTERVAL"); v != "" {
+		indexerPollInterval, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || indexerPollInterval <= 0 {
+			l.Error("INDEXER_POLL_INTERVAL is not positive integer", "error", err)
+			return nil, errors.New("INDEXER_POLL_INTERVAL is not positive integer")
+		}
 	}
 
 	return &Config{
-		Host:                host,
-		Port:                port,
-		DBURI:               dbURI,
-		AMQPURI:             amqpURI,
-		CacheUpdateInterval: time.Duration(intCacheUpdateInterval) * time.Second,
-		UserAddress:         userAddress,
-		UserPrivateKey:      userPrivateKey,
-		NetworkName:         networkName,
-		InfuraApiKey:        infuraApiKey,
-		ChainID:             intChainID,
-		ContractAddress:     contractAddress,
-		ContractABIPath:     contractABIPath,
+		Host:                   host,
+		Port:                   port,
+		DBURI:                  dbURI,
+		QueueBackend:           queueBackend,
+		AMQPURI:                amqpURI,
+		CacheUpdateInterval:    time.Duration(intCacheUpdateInterval) * time.Second,
+		ViewCacheTTL:           time.Duration(viewCacheTTL) * time.Second,
+		UserAddress:            userAddress,
+		UserPrivateKey:         userPrivateKey,
+		SignerType:             signerType,
+		KeystorePath:           keystorePath,
+		KeystorePasswordFile:   keystorePasswordFile,
+		RemoteSignerURL:        remoteSignerURL,
+		NetworkName:            networkName,
+		InfuraApiKey:           infuraApiKey,
+		RPCMaxBlockLag:         rpcMaxBlockLag,
+		RPCHealthCheckInterval: time.Duration(rpcHealthCheckInterval) * time.Second,
+		Chains:                 chains,
+		ContractAddress:        contractAddress,
+		ContractABIPath:        contractABIPath,
+		TxMonitorInterval:      time.Duration(txMonitorInterval) * time.Second,
+		BalanceCheckInterval:   time.Duration(balanceCheckInterval) * time.Second,
+		LowBalanceWebhookURL:   os.Getenv("LOW_BALANCE_WEBHOOK_URL"),
+		OutboxRelayInterval:    time.Duration(outboxRelayInterval) * time.Second,
+		EventsExchange:         eventsExchange,
+		ReceiptRetry:           receiptRetry,
+		TxStuckAfter:           time.Duration(txStuckAfter) * time.Second,
+		TxMaxSpeedUps:          txMaxSpeedUps,
+		IndexerBlockRange:      indexerBlockRange,
+		IndexerPollInterval:    time.Duration(indexerPollInterval) * time.Second,
+		BatchMaxItems:          batchMaxItems,
+		BatchChunkSize:         batchChunkSize,
+		AdminToken:             os.Getenv("ADMIN_TOKEN"),
+		MinterRole:             minterRole,
 	}, nil
 }
+
+// loadChain reads the RPC endpoints, confirmations, fee policy and low balance threshold of a chain from the variables with the prefix.
+// Unset variables keep the values of defaults.
+func loadChain(prefix string, chainID int64, defaults ChainConfig) (ChainConfig, error) {
+	var (
+		l     = slog.Default()
+		chain = ChainConfig{
+			ChainID:            chainID,
+			Finality:           defaults.Finality,
+			GasLimitMultiplier: defaults.GasLimitMultiplier,
+			MaxFeePerGas:       defaults.MaxFeePerGas,
+			LowBalance:         defaults.LowBalance,
+		}
+		err error
+	)
+
+	for _, rpcURL := range strings.Split(os.Getenv(prefix+"RPC_URLS"), ",") {
+		if rpcURL = strings.TrimSpace(rpcURL); rpcURL != "" {
+			chain.RPCURLs = append(chain.RPCURLs, rpcURL)
+		}
+	}
+
+	if v := os.Getenv(prefix + "GAS_LIMIT_MULTIPLIER"); v != "" {
+		chain.GasLimitMultiplier, err = strconv.ParseFloat(v, 64)
+		if err != nil || chain.GasLimitMultiplier < 1 {
+			l.Error(prefix+"GAS_LIMIT_MULTIPLIER is not a number >= 1", "error", err)
+			return ChainConfig{}, errors.New(prefix + "GAS_LIMIT_MULTIPLIER is not a number >= 1")
+		}
+	}
+
+	if v := os.Getenv(prefix + "MAX_FEE_PER_GAS_GWEI"); v != "" {
+		maxFeePerGasGwei, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || maxFeePerGasGwei <= 0 {
+			l.Error(prefix+"MAX_FEE_PER_GAS_GWEI is not positive integer", "error", err)
+			return ChainConfig{}, errors.New(prefix + "MAX_FEE_PER_GAS_GWEI is not positive integer")
+		}
+		chain.MaxFeePerGas = new(big.Int).Mul(big.NewInt(maxFeePerGasGwei), big.NewInt(1e9))
+	}
+
+	if v := os.Getenv(prefix + "LOW_BALANCE_GWEI"); v != "" {
+		lowBalanceGwei, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || lowBalanceGwei < 0 {
+			l.Error(prefix+"LOW_BALANCE_GWEI is not non-negative integer", "error", err)
+			return ChainConfig{}, errors.New(prefix + "LOW_BALANCE_GWEI is not non-negative integer")
+		}
+		chain.LowBalance = new(big.Int).Mul(big.NewInt(lowBalanceGwei), big.NewInt(1e9))
+	}
+
+	if v := os.Getenv(prefix + "CONFIRMATIONS"); v == "finalized" {
+		chain.Finality = blockchain.Finality{Finalized: true}
+	} else if v != "" {
+		depth, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			l.Error(prefix+"CONFIRMATIONS is not non-negative integer or finalized", "error", err)
+			return ChainConfig{}, errors.New(prefix + "CONFIRMATIONS is not non-negative integer or finalized")
+		}
+		chain.Finality = blockchain.Finality{Depth: depth}
+	}
+
+	// block numbers differ between chains, the start block is not taken from the primary chain
+	if v := os.Getenv(prefix + "INDEXER_START_BLOCK"); v != "" {
+		startBlock, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			l.Error(prefix+"INDEXER_START_BLOCK is not non-negative integer", "error", err)
+			return ChainConfig{}, errors.New(prefix + "INDEXER_START_BLOCK is not non-negative integer")
+		}
+		chain.IndexerStartBlock = &startBlock
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,200 @@
+package messaging
+
+import (
+	"bytes"
+	"maps"
+	"sync"
+	"time"
+)
+
+// Memory keeps the queues in the process. The messages are lost when the process stops,
+// it serves the tests and a single binary started without a broker.
+type Memory struct {
+	topology  *topology
+	queues    map[string]*memoryQueue
+	done      chan struct{} // closed by Close
+	closeOnce sync.Once
+	mu        sync.Mutex
+}
+
+type memoryQueue struct {
+	messages []memoryMessage
+	ready    chan struct{} // signalled when a message is added
+}
+
+type memoryMessage struct {
+	body    []byte
+	headers Headers
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		topology: newTopology(),
+		queues:   make(map[string]*memoryQueue),
+		done:     make(chan struct{}),
+	}
+}
+
+func (m *Memory) Declare(queue string, policy RetryPolicy) error {
+	m.topology.declare(queue, policy)
+	return nil
+}
+
+func (m *Memory) Publish(queue string, body []byte, headers Headers) error {
+	target, delay, err := m.topology.route(queue)
+	if err != nil {
+		return err
+	}
+
+	msg := memoryMessage{body: bytes.Clone(body), headers: maps.Clone(headers)}
+	if delay == 0 {
+		m.push(target, msg, false)
+		return nil
+	}
+
+	time.AfterFunc(delay, func() { m.push(target, msg, false) })
+	return nil
+}
+
+// Consume returns the messages of the queue. The returned channel is closed by Close,
+// unacked messages are lost then.
+func (m *Memory) Consume(queue string) (<-chan Message, error) {
+	if _, _, err := m.topology.route(queue); err != nil {
+		return nil, err
+	}
+
+	out := make(chan Message)
+	go m.deliver(queue, out)
+
+	return out, nil
+}
+
+func (m *Memory) deliver(queue string, out chan<- Message) {
+	defer close(out)
+
+	m.mu.Lock()
+	ready := m.queue(queue).ready
+	m.mu.Unlock()
+
+	for {
+		msg, ok := m.pop(queue)
+		if !ok {
+			select {
+			case <-ready:
+				continue
+			case <-m.done:
+				return
+			}
+		}
+
+		message := NewMessage(msg.body, msg.headers,
+			func() error { return nil },
+			func(requeue bool) error {
+				if requeue {
+					m.push(queue, msg, true)
+				}
+				return nil
+			},
+		)
+
+		select {
+		case out <- message:
+		case <-m.done:
+			return
+		}
+	}
+}
+
+func (m *Memory) Peek(queue string, limit int) ([]Message, error) {
+	if _, _, err := m.topology.route(queue); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q := m.queue(queue)
+	msgs := make([]Message, 0, min(limit, len(q.messages)))
+	for _, msg := range q.messages[:min(limit, len(q.messages))] {
+		msgs = append(msgs, NewMessage(msg.body, msg.headers, nil, nil))
+	}
+	return msgs, nil
+}
+
+func (m *Memory) Replay(queue string, limit int) (int, error) {
+	if _, _, err := m.topology.route(queue); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deadLetters := m.queue(DeadLetterQueueName(queue))
+	n := min(limit, len(deadLetters.messages))
+
+	q := m.queue(queue)
+	for _, msg := range deadLetters.messages[:n] {
+		q.messages = append(q.messages, memoryMessage{body: msg.body})
+	}
+	deadLetters.messages = deadLetters.messages[n:]
+	if n > 0 {
+		q.signal()
+	}
+
+	return n, nil
+}
+
+// Connected is always true, the queues live in the process
+func (m *Memory) Connected() bool {
+	return true
+}
+
+func (m *Memory) Close() {
+	m.closeOnce.Do(func() { close(m.done) })
+}
+
+// push adds the message to the queue, a requeued message goes to the front
+func (m *Memory) push(queue string, msg memoryMessage, front bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q := m.queue(queue)
+	if front {
+		q.messages = append([]memoryMessage{msg}, q.messages...)
+	} else {
+		q.messages = append(q.messages, msg)
+	}
+	q.signal()
+}
+
+func (m *Memory) pop(queue string) (memoryMessage, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q := m.queue(queue)
+	if len(q.messages) == 0 {
+		return memoryMessage{}, false
+	}
+
+	msg := q.messages[0]
+	q.messages = q.messages[1:]
+	return msg, true
+}
+
+// queue returns the queue of the name, m.mu has to be held
+func (m *Memory) queue(name string) *memoryQueue {
+	q, ok := m.queues[name]
+	if !ok {
+		q = &memoryQueue{ready: make(chan struct{}, 1)}
+		m.queues[name] = q
+	}
+	return q
+}
+
+// signal wakes up a consumer waiting for the queue, a consumer that is busy finds the message on its next pop
+func (q *memoryQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,102 @@
+package messaging
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var testPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 20 * time.Millisecond, MaxDelay: 40 * time.Millisecond}
+
+func receive(t *testing.T, msgs <-chan Message) Message {
+	t.Helper()
+
+	select {
+	case msg := <-msgs:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return Message{}
+	}
+}
+
+func TestMemory_NackRequeuesMessage(t *testing.T) {
+	m := NewMemory()
+	defer m.Close()
+	require.NoError(t, m.Declare("token_queue", testPolicy))
+
+	msgs, err := m.Consume("token_queue")
+	require.NoError(t, err)
+
+	require.NoError(t, m.Publish("token_queue", []byte("first"), nil))
+	msg := receive(t, msgs)
+	assert.Equal(t, "first", string(msg.Body))
+	require.NoError(t, msg.Nack(true))
+
+	msg = receive(t, msgs)
+	assert.Equal(t, "first", string(msg.Body))
+	require.NoError(t, msg.Ack())
+
+	select {
+	case msg := <-msgs:
+		t.Fatalf("unexpected message %q", msg.Body)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMemory_RetryQueueDelaysMessage(t *testing.T) {
+	m := NewMemory()
+	defer m.Close()
+	require.NoError(t, m.Declare("token_queue", testPolicy))
+
+	msgs, err := m.Consume("token_queue")
+	require.NoError(t, err)
+
+	publishedAt := time.Now()
+	retryQueue := RetryQueueName("token_queue", testPolicy.Delay(1))
+	require.NoError(t, m.Publish(retryQueue, []byte("retry"), Headers{HeaderRetryCount: int32(2)}))
+
+	msg := receive(t, msgs)
+	assert.GreaterOrEqual(t, time.Since(publishedAt), testPolicy.Delay(1))
+	assert.Equal(t, 2, RetryCount(msg))
+}
+
+func TestMemory_ReplayMovesDeadLetters(t *testing.T) {
+	m := NewMemory()
+	defer m.Close()
+	require.NoError(t, m.Declare("token_queue", testPolicy))
+
+	deadLetters := DeadLetterQueueName("token_queue")
+	for _, body := range []string{"a", "b", "c"} {
+		require.NoError(t, m.Publish(deadLetters, []byte(body), Headers{HeaderLastError: "failed"}))
+	}
+
+	peeked, err := m.Peek(deadLetters, 2)
+	require.NoError(t, err)
+	require.Len(t, peeked, 2)
+	assert.Equal(t, "failed", peeked[0].Headers[HeaderLastError])
+
+	moved, err := m.Replay("token_queue", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, moved)
+
+	msgs, err := m.Consume("token_queue")
+	require.NoError(t, err)
+	msg := receive(t, msgs)
+	assert.Equal(t, "a", string(msg.Body))
+	assert.Zero(t, RetryCount(msg))
+
+	left, err := m.Peek(deadLetters, 10)
+	require.NoError(t, err)
+	assert.Len(t, left, 1)
+}
+
+func TestMemory_UnknownQueue(t *testing.T) {
+	m := NewMemory()
+	defer m.Close()
+
+	assert.ErrorIs(t, m.Publish("token_queue", []byte("a"), nil), ErrUnknownQueue)
+	_, err := m.Consume("token_queue")
+	assert.ErrorIs(t, err, ErrUnknownQueue)
+}
@@ -0,0 +1,76 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownQueue is returned when a message is published to a queue that was not declared
+var ErrUnknownQueue = errors.New("queue is not declared")
+
+// Headers are the metadata of a message, the retry state is carried in them
+type Headers map[string]any
+
+// Queue is a durable work queue backend. Every work queue is declared with retry queues that hold messages
+// for the delays of a RetryPolicy and return them to the work queue, and with a dead letter queue.
+type Queue interface {
+	// Declare declares the work queue with its retry queues and its dead letter queue
+	Declare(queue string, policy RetryPolicy) error
+	// Publish stores the message in the queue. A message published to a retry queue is delivered
+	// by its work queue after the delay of the retry queue.
+	Publish(queue string, body []byte, headers Headers) error
+	// Consume returns the messages of the queue. A message is delivered to one consumer at a time,
+	// it is delivered again when it is nacked with requeue.
+	Consume(queue string) (<-chan Message, error)
+	// Peek returns up to limit messages of the queue without removing them
+	Peek(queue string, limit int) ([]Message, error)
+	// Replay moves up to limit messages of the dead letter queue of the work queue back to it with
+	// a fresh retry count and returns how many were moved
+	Replay(queue string, limit int) (int, error)
+	// Connected reports whether the backend is available
+	Connected() bool
+	Close()
+}
+
+// TopicPublisher publishes messages to a topic exchange for other services, they bind their own queues
+// to it by routing key. Only the broker backends implement it.
+type TopicPublisher interface {
+	DeclareExchange(exchange string) error
+	PublishTopic(exchange, routingKey string, body []byte) error
+}
+
+// Message is a message received from a queue, it has to be acked or nacked
+type Message struct {
+	Body    []byte
+	Headers Headers
+	ack     func() error
+	nack    func(requeue bool) error
+}
+
+// NewMessage creates a message acked and nacked by the functions of the backend. Messages without them,
+// like the ones returned by Peek, are not held by the backend and ignore Ack and Nack.
+func NewMessage(body []byte, headers Headers, ack func() error, nack func(requeue bool) error) Message {
+	return Message{Body: body, Headers: headers, ack: ack, nack: nack}
+}
+
+// Ack removes the message from the queue
+func (m Message) Ack() error {
+	if m.ack == nil {
+		return nil
+	}
+	if err := m.ack(); err != nil {
+		return fmt.Errorf("failed to ack message: %w", err)
+	}
+	return nil
+}
+
+// Nack returns the message to the queue when requeue is set, otherwise it is dropped
+func (m Message) Nack(requeue bool) error {
+	if m.nack == nil {
+		return nil
+	}
+	if err := m.nack(requeue); err != nil {
+		return fmt.Errorf("failed to nack message: %w", err)
+	}
+	return nil
+}
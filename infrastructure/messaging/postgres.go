@@ -0,0 +1,233 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// postgresPollInterval is how often an idle consumer looks for new messages
+	postgresPollInterval = time.Second
+	// postgresLease is how long a delivered message is hidden from the other consumers. A message that is
+	// neither acked nor nacked within the lease, for example because the process died, is delivered again.
+	postgresLease = 5 * time.Minute
+	// postgresBatch is how many messages a consumer locks at once
+	postgresBatch = 10
+)
+
+// Postgres keeps the queues in the queue_messages table. Consumers lock messages with
+// SELECT ... FOR UPDATE SKIP LOCKED, so several instances of the service can share the queues.
+type Postgres struct {
+	db        *pgxpool.Pool
+	topology  *topology
+	done      chan struct{} // closed by Close
+	closeOnce sync.Once
+}
+
+func NewPostgres(db *pgxpool.Pool) *Postgres {
+	return &Postgres{
+		db:       db,
+		topology: newTopology(),
+		done:     make(chan struct{}),
+	}
+}
+
+func (p *Postgres) Declare(queue string, policy RetryPolicy) error {
+	p.topology.declare(queue, policy)
+	return nil
+}
+
+func (p *Postgres) Publish(queue string, body []byte, headers Headers) error {
+	target, delay, err := p.topology.route(queue)
+	if err != nil {
+		return err
+	}
+
+	if headers == nil {
+		headers = Headers{}
+	}
+	encodedHeaders, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode headers: %w", err)
+	}
+
+	query := `INSERT INTO queue_messages (queue, body, headers, available_at)
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))`
+
+	if _, err := p.db.Exec(context.Background(), query, target, body, encodedHeaders, delay.Seconds()); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	return nil
+}
+
+// Consume polls the queue for messages. The returned channel is closed by Close.
+func (p *Postgres) Consume(queue string) (<-chan Message, error) {
+	if _, _, err := p.topology.route(queue); err != nil {
+		return nil, err
+	}
+
+	out := make(chan Message)
+	go p.deliver(queue, out)
+
+	return out, nil
+}
+
+func (p *Postgres) deliver(queue string, out chan<- Message) {
+	defer close(out)
+
+	for {
+		msgs, err := p.lock(queue)
+		if err != nil {
+			slog.Default().Error("failed to fetch queue messages", slog.String("queue", queue), slog.Any("error", err))
+		}
+
+		for _, msg := range msgs {
+			select {
+			case out <- msg:
+			case <-p.done:
+				return
+			}
+		}
+
+		if len(msgs) == postgresBatch {
+			// there may be more
+			continue
+		}
+
+		select {
+		case <-time.After(postgresPollInterval):
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// lock leases the next available messages of the queue
+func (p *Postgres) lock(queue string) ([]Message, error) {
+	query := `UPDATE queue_messages SET locked_until = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM queue_messages
+			WHERE queue = $1 AND available_at <= NOW() AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, body, headers`
+
+	rows, err := p.db.Query(context.Background(), query, queue, postgresLease.Seconds(), postgresBatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock messages: %w", err)
+	}
+	defer rows.Close()
+
+	var msgs []Message
+	for rows.Next() {
+		var (
+			id      int64
+			body    []byte
+			headers Headers
+		)
+		if err := rows.Scan(&id, &body, &headers); err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+
+		msgs = append(msgs, NewMessage(body, headers,
+			func() error { return p.ack(id) },
+			func(requeue bool) error { return p.nack(id, requeue) },
+		))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	return msgs, nil
+}
+
+func (p *Postgres) ack(id int64) error {
+	if _, err := p.db.Exec(context.Background(), `DELETE FROM queue_messages WHERE id = $1`, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *Postgres) nack(id int64, requeue bool) error {
+	if !requeue {
+		return p.ack(id)
+	}
+
+	if _, err := p.db.Exec(context.Background(), `UPDATE queue_messages SET locked_until = NULL WHERE id = $1`, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *Postgres) Peek(queue string, limit int) ([]Message, error) {
+	if _, _, err := p.topology.route(queue); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT body, headers FROM queue_messages WHERE queue = $1 ORDER BY id LIMIT $2`
+
+	rows, err := p.db.Query(context.Background(), query, queue, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	defer rows.Close()
+
+	var msgs []Message
+	for rows.Next() {
+		var (
+			body    []byte
+			headers Headers
+		)
+		if err := rows.Scan(&body, &headers); err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		msgs = append(msgs, NewMessage(body, headers, nil, nil))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	return msgs, nil
+}
+
+func (p *Postgres) Replay(queue string, limit int) (int, error) {
+	if _, _, err := p.topology.route(queue); err != nil {
+		return 0, err
+	}
+
+	query := `UPDATE queue_messages SET queue = $1, headers = '{}', available_at = NOW(), locked_until = NULL
+		WHERE id IN (
+			SELECT id FROM queue_messages
+			WHERE queue = $2
+			ORDER BY id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)`
+
+	tag, err := p.db.Exec(context.Background(), query, queue, DeadLetterQueueName(queue), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to replay messages: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// Connected reports whether the database answers
+func (p *Postgres) Connected() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	return p.db.Ping(ctx) == nil
+}
+
+func (p *Postgres) Close() {
+	p.closeOnce.Do(func() { close(p.done) })
+}
@@ -0,0 +1,67 @@
+package messaging
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// HeaderRetryCount is the number of times the message was retried
+	HeaderRetryCount = "x-retry-count"
+	// HeaderLastError is why the last attempt to handle the message failed
+	HeaderLastError = "x-last-error"
+	// HeaderDeadLetteredAt is when the message was moved to the dead letter queue, RFC 3339
+	HeaderDeadLetteredAt = "x-dead-lettered-at"
+)
+
+// RetryPolicy is the backoff of a work queue. The delay doubles from BaseDelay up to MaxDelay,
+// after MaxAttempts the message is moved to the dead letter queue.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// Delays returns the distinct delays of the policy, one retry queue is declared for each of them
+func (p RetryPolicy) Delays() []time.Duration {
+	var delays []time.Duration
+	for delay := p.BaseDelay; ; delay *= 2 {
+		if delay >= p.MaxDelay {
+			return append(delays, p.MaxDelay)
+		}
+		delays = append(delays, delay)
+	}
+}
+
+// Delay returns how long the message waits before the attempt after retryCount retries
+func (p RetryPolicy) Delay(retryCount int) time.Duration {
+	delays := p.Delays()
+	return delays[min(retryCount, len(delays)-1)]
+}
+
+// RetryQueueName is the queue the messages of the work queue wait the delay in
+func RetryQueueName(queue string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%s", queue, delay)
+}
+
+// DeadLetterQueueName is the queue the messages of the work queue end up in after the last attempt
+func DeadLetterQueueName(queue string) string {
+	return queue + ".dlq"
+}
+
+// RetryCount returns the number of times the message was retried
+func RetryCount(msg Message) int {
+	switch v := msg.Headers[HeaderRetryCount].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	case float64:
+		// headers stored as JSON
+		return int(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,24 @@
+package messaging
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_DelaysDoubleUpToMax(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 5 * time.Second, MaxDelay: 30 * time.Second}
+
+	assert.Equal(t, []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 30 * time.Second}, policy.Delays())
+	assert.Equal(t, 5*time.Second, policy.Delay(0))
+	assert.Equal(t, 20*time.Second, policy.Delay(2))
+	assert.Equal(t, 30*time.Second, policy.Delay(9))
+}
+
+func TestRetryCount(t *testing.T) {
+	assert.Equal(t, 0, RetryCount(Message{}))
+	assert.Equal(t, 3, RetryCount(Message{Headers: Headers{HeaderRetryCount: int32(3)}}))
+	assert.Equal(t, 2, RetryCount(Message{Headers: Headers{HeaderRetryCount: float64(2)}}))
+	assert.Equal(t, "token_queue.retry.5s", RetryQueueName("token_queue", 5*time.Second))
+	assert.Equal(t, "token_queue.dlq", DeadLetterQueueName("token_queue"))
+}
@@ -0,0 +1,52 @@
+package messaging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// topology routes the publications of the backends without a broker: a message published to a retry queue
+// is stored in its work queue with the delay of the retry queue
+type topology struct {
+	mu      sync.RWMutex
+	queues  map[string]struct{}   // work queues and dead letter queues
+	retries map[string]retryRoute // retry queue by name
+}
+
+type retryRoute struct {
+	queue string
+	delay time.Duration
+}
+
+func newTopology() *topology {
+	return &topology{
+		queues:  make(map[string]struct{}),
+		retries: make(map[string]retryRoute),
+	}
+}
+
+func (t *topology) declare(queue string, policy RetryPolicy) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.queues[queue] = struct{}{}
+	t.queues[DeadLetterQueueName(queue)] = struct{}{}
+	for _, delay := range policy.Delays() {
+		t.retries[RetryQueueName(queue, delay)] = retryRoute{queue: queue, delay: delay}
+	}
+}
+
+// route returns the queue a message published to the queue is stored in and how long it waits there
+func (t *topology) route(queue string) (string, time.Duration, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if _, ok := t.queues[queue]; ok {
+		return queue, 0, nil
+	}
+	if route, ok := t.retries[queue]; ok {
+		return route.queue, route.delay, nil
+	}
+	return "", 0, fmt.Errorf("%w: %s", ErrUnknownQueue, queue)
+}
@@ -0,0 +1,18 @@
+package rabbit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	connectionUp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "rabbitmq_connected",
+		Help: "Whether the connection to RabbitMQ is up (1) or being re-established (0).",
+	})
+
+	reconnectsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "rabbitmq_reconnects_total",
+		Help: "Connections to RabbitMQ re-established after the broker closed them.",
+	})
+)
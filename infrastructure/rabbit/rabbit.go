@@ -1,36 +1,74 @@
 package rabbit
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/rabbitmq/amqp091-go"
+	"nft_service/infrastructure/messaging"
+	"strconv"
 	"sync"
+	"time"
 )
 
+// ErrNotConnected is returned while the connection to RabbitMQ is being re-established
+var ErrNotConnected = errors.New("rabbitmq is not connected")
+
+// publishTimeout is how long a publication waits for the confirmation of the broker
+const publishTimeout = 5 * time.Second
+
+// PublishError is returned when the broker did not accept a message: it could not be routed to a queue,
+// the broker nacked it or did not confirm it in time. The message may or may not have been stored.
+type PublishError struct {
+	Queue  string // routing key, the queue name for messages sent to a queue
+	Reason string
+}
+
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("message to queue %s was not accepted: %s", e.Queue, e.Reason)
+}
+
+// RabbitMQ is a connection with one channel that is re-established when the broker closes it.
+// The declared queues are declared again and the consumers resume on the new channel.
 type RabbitMQ struct {
-	conn    *amqp091.Connection
-	channel *amqp091.Channel
-	mu      sync.Mutex
+	uri       string
+	conn      *amqp091.Connection
+	channel   *amqp091.Channel    // nil while disconnected, in confirm mode
+	returns   chan amqp091.Return // unroutable messages returned on the channel
+	returned  map[string]string   // reply text of the returned messages by message id, until their publication takes it
+	returnsMu sync.Mutex          // guards returned and the draining of returns
+	published uint64              // id of the last published message
+	topology  []declaration       // declared again after a reconnect
+	ready     chan struct{}       // closed while connected, replaced when the connection is lost
+	done      chan struct{}       // closed by Close
+	closeOnce sync.Once
+	mu        sync.Mutex
 }
 
+// declaration declares queues on a channel
+type declaration func(channel *amqp091.Channel) error
+
 func NewRabbitMQ(uri string) (*RabbitMQ, error) {
-	conn, err := amqp091.Dial(uri)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	r := &RabbitMQ{
+		uri:      uri,
+		returned: make(map[string]string),
+		ready:    make(chan struct{}),
+		done:     make(chan struct{}),
 	}
 
-	channel, err := conn.Channel()
+	conn, channel, err := r.dial()
 	if err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("failed to create channel: %w", err)
+		return nil, err
 	}
 
-	return &RabbitMQ{
-		conn:    conn,
-		channel: channel,
-	}, nil
+	r.connected(conn, channel)
+
+	return r, nil
 }
 
 func (r *RabbitMQ) Close() {
+	r.closeOnce.Do(func() { close(r.done) })
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if r.channel != nil {
@@ -41,48 +79,188 @@ func (r *RabbitMQ) Close() {
 	}
 }
 
-func (r *RabbitMQ) DeclareQueue(name string) (amqp091.Queue, error) {
+// Connected reports whether the connection to RabbitMQ is up
+func (r *RabbitMQ) Connected() bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	return r.channel != nil
+}
 
-	queue, err := r.channel.QueueDeclare(
-		name,  // name
-		true,  // durable
-		false, // auto-delete
-		false, // exclusive
-		false, // no-wait
-		nil,   // arguments
-	)
-	if err != nil {
-		return amqp091.Queue{}, fmt.Errorf("failed to declare queue: %w", err)
-	}
-	return queue, nil
+// Declare declares the work queue with its retry queues and its dead letter queue
+func (r *RabbitMQ) Declare(queue string, policy messaging.RetryPolicy) error {
+	return r.declare(func(channel *amqp091.Channel) error {
+		_, err := channel.QueueDeclare(
+			queue, // name
+			true,  // durable
+			false, // auto-delete
+			false, // exclusive
+			false, // no-wait
+			nil,   // arguments
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare queue: %w", err)
+		}
+
+		return declareRetryQueues(channel, queue, policy)
+	})
 }
 
-func (r *RabbitMQ) Publish(queue string, body []byte) error {
+// declare runs the declaration and remembers it for the reconnects
+func (r *RabbitMQ) declare(d declaration) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	err := r.channel.Publish(
-		"",    // exchange
-		queue, // routing key
-		false, // mandatory
-		false, // immediate
-		amqp091.Publishing{
-			ContentType: "text/plain",
-			Body:        body,
-		},
+	if r.channel == nil {
+		return ErrNotConnected
+	}
+
+	if err := d(r.channel); err != nil {
+		return err
+	}
+
+	r.topology = append(r.topology, d)
+	return nil
+}
+
+func (r *RabbitMQ) Publish(queue string, body []byte, headers messaging.Headers) error {
+	return r.publish("", queue, true, amqp091.Publishing{
+		ContentType: "text/plain",
+		Headers:     amqp091.Table(headers),
+		Body:        body,
+	})
+}
+
+// DeclareExchange declares a durable topic exchange, the subscribers bind their own queues to it
+func (r *RabbitMQ) DeclareExchange(exchange string) error {
+	return r.declare(func(channel *amqp091.Channel) error {
+		err := channel.ExchangeDeclare(
+			exchange, // name
+			"topic",  // kind
+			true,     // durable
+			false,    // auto-delete
+			false,    // internal
+			false,    // no-wait
+			nil,      // arguments
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare exchange: %w", err)
+		}
+		return nil
+	})
+}
+
+// PublishTopic publishes the JSON message to the topic exchange and waits until the broker confirms it.
+// The message is not mandatory, the broker drops it when no queue is bound to the routing key.
+func (r *RabbitMQ) PublishTopic(exchange, routingKey string, body []byte) error {
+	return r.publish(exchange, routingKey, false, amqp091.Publishing{
+		ContentType: "application/json",
+		Body:        body,
+	})
+}
+
+// confirmation is the pending confirmation of a published message
+type confirmation interface {
+	WaitContext(ctx context.Context) (bool, error)
+}
+
+// publish sends a persistent message with the routing key and waits until the broker confirms it.
+// A *PublishError is returned when a mandatory message was returned as unroutable or when the message
+// was nacked or not confirmed in time. Only sending holds the lock, publications wait for their
+// confirmations concurrently.
+func (r *RabbitMQ) publish(exchange, routingKey string, mandatory bool, msg amqp091.Publishing) error {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	r.mu.Lock()
+	if r.channel == nil {
+		r.mu.Unlock()
+		return ErrNotConnected
+	}
+
+	r.published++
+	messageID := strconv.FormatUint(r.published, 10)
+	returns := r.returns
+
+	msg.DeliveryMode = amqp091.Persistent
+	msg.MessageId = messageID
+
+	confirmation, err := r.channel.PublishWithDeferredConfirmWithContext(ctx,
+		exchange,   // exchange
+		routingKey, // routing key
+		mandatory,  // mandatory
+		false,      // immediate
+		msg,
 	)
+	r.mu.Unlock()
 	if err != nil {
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
+
+	return r.awaitConfirmation(ctx, confirmation, returns, messageID, routingKey)
+}
+
+// awaitConfirmation waits for the confirmation of the message and checks whether the broker returned it
+func (r *RabbitMQ) awaitConfirmation(ctx context.Context, confirmation confirmation, returns <-chan amqp091.Return,
+	messageID, routingKey string,
+) error {
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return &PublishError{Queue: routingKey, Reason: "not confirmed within " + publishTimeout.String()}
+	}
+	if !acked {
+		return &PublishError{Queue: routingKey, Reason: "nacked by the broker"}
+	}
+
+	if replyText, ok := r.takeReturn(returns, messageID); ok {
+		return &PublishError{Queue: routingKey, Reason: fmt.Sprintf("unroutable: %s", replyText)}
+	}
+
 	return nil
 }
 
-func (r *RabbitMQ) Consume(queue string) (<-chan amqp091.Delivery, error) {
+// takeReturn moves the returned messages of the channel to r.returned and takes the one of the message id.
+// The broker returns an unroutable message before it confirms it, so it is there once the message is confirmed.
+func (r *RabbitMQ) takeReturn(returns <-chan amqp091.Return, messageID string) (string, bool) {
+	r.returnsMu.Lock()
+	defer r.returnsMu.Unlock()
+
+drain:
+	for {
+		select {
+		case returned := <-returns:
+			r.returned[returned.MessageId] = returned.ReplyText
+		default:
+			break drain
+		}
+	}
+
+	replyText, ok := r.returned[messageID]
+	delete(r.returned, messageID)
+	return replyText, ok
+}
+
+// Consume returns the messages of the queue. The returned channel survives reconnects, it is closed by Close.
+// Messages received before a reconnect can not be acked anymore, the broker delivers them again.
+func (r *RabbitMQ) Consume(queue string) (<-chan messaging.Message, error) {
+	msgs, err := r.consume(queue)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan messaging.Message)
+	go r.forward(queue, msgs, out)
+
+	return out, nil
+}
+
+func (r *RabbitMQ) consume(queue string) (<-chan amqp091.Delivery, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if r.channel == nil {
+		return nil, ErrNotConnected
+	}
+
 	msgs, err := r.channel.Consume(
 		queue, // queue
 		"",    // consumer
@@ -97,3 +275,10 @@ func (r *RabbitMQ) Consume(queue string) (<-chan amqp091.Delivery, error) {
 	}
 	return msgs, nil
 }
+
+func newMessage(delivery amqp091.Delivery) messaging.Message {
+	return messaging.NewMessage(delivery.Body, messaging.Headers(delivery.Headers),
+		func() error { return delivery.Ack(false) },
+		func(requeue bool) error { return delivery.Nack(false, requeue) },
+	)
+}
@@ -0,0 +1,138 @@
+package rabbit
+
+import (
+	"context"
+	"fmt"
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nft_service/infrastructure/messaging"
+	"os"
+	"testing"
+	"time"
+)
+
+type fakeConfirmation struct {
+	acked bool
+	err   error
+}
+
+func (f fakeConfirmation) WaitContext(_ context.Context) (bool, error) {
+	return f.acked, f.err
+}
+
+func TestPublishError(t *testing.T) {
+	var err error = &PublishError{Queue: "token_queue", Reason: "unroutable: NO_ROUTE"}
+
+	var publishErr *PublishError
+	assert.ErrorAs(t, fmt.Errorf("failed to publish outbox message: %w", err), &publishErr)
+	assert.Equal(t, "message to queue token_queue was not accepted: unroutable: NO_ROUTE", err.Error())
+}
+
+func TestAwaitConfirmation(t *testing.T) {
+	r := &RabbitMQ{returned: make(map[string]string)}
+	returns := make(chan amqp091.Return, 4)
+	ctx := context.Background()
+
+	assert.NoError(t, r.awaitConfirmation(ctx, fakeConfirmation{acked: true}, returns, "1", "token_queue"))
+
+	var publishErr *PublishError
+	err := r.awaitConfirmation(ctx, fakeConfirmation{acked: false}, returns, "2", "token_queue")
+	require.ErrorAs(t, err, &publishErr)
+	assert.Equal(t, "nacked by the broker", publishErr.Reason)
+
+	err = r.awaitConfirmation(ctx, fakeConfirmation{err: context.DeadlineExceeded}, returns, "3", "token_queue")
+	require.ErrorAs(t, err, &publishErr)
+	assert.Contains(t, publishErr.Reason, "not confirmed")
+}
+
+func TestAwaitConfirmation_Returned(t *testing.T) {
+	r := &RabbitMQ{returned: make(map[string]string)}
+	returns := make(chan amqp091.Return, 4)
+	ctx := context.Background()
+
+	// the return of another publication is kept for it
+	returns <- amqp091.Return{MessageId: "8", ReplyText: "NO_ROUTE"}
+	returns <- amqp091.Return{MessageId: "7", ReplyText: "NO_ROUTE"}
+
+	var publishErr *PublishError
+	err := r.awaitConfirmation(ctx, fakeConfirmation{acked: true}, returns, "7", "token_queue")
+	require.ErrorAs(t, err, &publishErr)
+	assert.Equal(t, "unroutable: NO_ROUTE", publishErr.Reason)
+
+	err = r.awaitConfirmation(ctx, fakeConfirmation{acked: true}, returns, "8", "transfer_queue")
+	require.ErrorAs(t, err, &publishErr)
+	assert.Equal(t, "transfer_queue", publishErr.Queue)
+
+	assert.NoError(t, r.awaitConfirmation(ctx, fakeConfirmation{acked: true}, returns, "9", "token_queue"))
+	assert.Empty(t, r.returned)
+}
+
+// newTestRabbitMQ connects to the broker of AMQP_URI, the test is skipped without one
+func newTestRabbitMQ(t *testing.T) *RabbitMQ {
+	t.Helper()
+
+	uri := os.Getenv("AMQP_URI")
+	if uri == "" {
+		t.Skip("AMQP_URI is not set")
+	}
+
+	r, err := NewRabbitMQ(uri)
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func TestRabbitMQ_Replay(t *testing.T) {
+	r := newTestRabbitMQ(t)
+
+	policy := messaging.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Second, MaxDelay: time.Second}
+	queue := fmt.Sprintf("replay_test_%d", time.Now().UnixNano())
+	require.NoError(t, r.Declare(queue, policy))
+	t.Cleanup(func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, name := range append([]string{queue, messaging.DeadLetterQueueName(queue)},
+			messaging.RetryQueueName(queue, policy.BaseDelay)) {
+			r.channel.QueueDelete(name, false, false, false)
+		}
+	})
+
+	for _, body := range []string{"first", "second"} {
+		require.NoError(t, r.Publish(messaging.DeadLetterQueueName(queue), []byte(body), nil))
+	}
+
+	type result struct {
+		replayed int
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		replayed, err := r.Replay(queue, 10)
+		done <- result{replayed, err}
+	}()
+
+	select {
+	case res := <-done:
+		require.NoError(t, res.err)
+		assert.Equal(t, 2, res.replayed)
+	case <-time.After(10 * time.Second):
+		t.Fatal("replay did not return")
+	}
+
+	// the lock is free again for the other publications
+	require.NoError(t, r.Publish(queue, []byte("third"), nil))
+
+	peeked, err := r.Peek(queue, 10)
+	require.NoError(t, err)
+	bodies := make([]string, 0, len(peeked))
+	for _, msg := range peeked {
+		bodies = append(bodies, string(msg.Body))
+	}
+	assert.ElementsMatch(t, []string{"first", "second", "third"}, bodies)
+
+	dead, err := r.Peek(messaging.DeadLetterQueueName(queue), 10)
+	require.NoError(t, err)
+	assert.Empty(t, dead)
+}
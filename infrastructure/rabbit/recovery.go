@@ -0,0 +1,172 @@
+package rabbit
+
+import (
+	"fmt"
+	"github.com/rabbitmq/amqp091-go"
+	"log/slog"
+	"nft_service/infrastructure/messaging"
+	"time"
+)
+
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+func (r *RabbitMQ) dial() (*amqp091.Connection, *amqp091.Channel, error) {
+	conn, err := amqp091.Dial(r.uri)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to create channel: %w", err)
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	return conn, channel, nil
+}
+
+// connected switches to the new connection, wakes up the consumers and watches the connection
+func (r *RabbitMQ) connected(conn *amqp091.Connection, channel *amqp091.Channel) {
+	connClosed := conn.NotifyClose(make(chan *amqp091.Error, 1))
+	channelClosed := channel.NotifyClose(make(chan *amqp091.Error, 1))
+	// drained by every publication, a return arrives before the confirmation of its message
+	returns := channel.NotifyReturn(make(chan amqp091.Return, 16))
+
+	// returns of the old channel whose publications gave up are not taken anymore
+	r.returnsMu.Lock()
+	clear(r.returned)
+	r.returnsMu.Unlock()
+
+	r.mu.Lock()
+	r.conn = conn
+	r.channel = channel
+	r.returns = returns
+	close(r.ready)
+	r.mu.Unlock()
+
+	connectionUp.Set(1)
+
+	go r.watch(connClosed, channelClosed)
+}
+
+// watch waits until the connection or the channel is closed and reconnects unless RabbitMQ was closed
+func (r *RabbitMQ) watch(connClosed, channelClosed chan *amqp091.Error) {
+	var reason *amqp091.Error
+
+	select {
+	case reason = <-connClosed:
+	case reason = <-channelClosed:
+	case <-r.done:
+		return
+	}
+
+	r.mu.Lock()
+	// a closed channel leaves the connection open, both are replaced
+	r.conn.Close()
+	r.conn = nil
+	r.channel = nil
+	r.ready = make(chan struct{})
+	r.mu.Unlock()
+
+	connectionUp.Set(0)
+
+	select {
+	case <-r.done:
+		return
+	default:
+	}
+
+	slog.Default().Error("rabbitmq connection lost", slog.Any("reason", reason))
+
+	r.reconnect()
+}
+
+// reconnect dials with a doubling delay until it succeeds, then declares the queues again
+func (r *RabbitMQ) reconnect() {
+	l := slog.Default()
+
+	for delay := minReconnectDelay; ; delay = min(delay*2, maxReconnectDelay) {
+		select {
+		case <-time.After(delay):
+		case <-r.done:
+			return
+		}
+
+		conn, channel, err := r.dial()
+		if err == nil {
+			err = r.redeclare(channel)
+			if err != nil {
+				conn.Close()
+			}
+		}
+		if err != nil {
+			l.Warn("failed to reconnect to rabbitmq", slog.Duration("retry_in", min(delay*2, maxReconnectDelay)),
+				slog.Any("error", err))
+			continue
+		}
+
+		reconnectsTotal.Inc()
+		r.connected(conn, channel)
+		l.Info("reconnected to rabbitmq")
+		return
+	}
+}
+
+func (r *RabbitMQ) redeclare(channel *amqp091.Channel) error {
+	r.mu.Lock()
+	topology := r.topology
+	r.mu.Unlock()
+
+	for _, d := range topology {
+		if err := d(channel); err != nil {
+			return fmt.Errorf("failed to declare queues: %w", err)
+		}
+	}
+	return nil
+}
+
+// forward passes the messages of the queue to out and consumes the queue again after every reconnect
+func (r *RabbitMQ) forward(queue string, msgs <-chan amqp091.Delivery, out chan<- messaging.Message) {
+	defer close(out)
+
+	for {
+		for msg := range msgs {
+			select {
+			case out <- newMessage(msg):
+			case <-r.done:
+				return
+			}
+		}
+
+		// the channel is closed, wait for the next connection
+		for {
+			r.mu.Lock()
+			ready := r.ready
+			r.mu.Unlock()
+
+			select {
+			case <-ready:
+			case <-r.done:
+				return
+			}
+
+			var err error
+			if msgs, err = r.consume(queue); err == nil {
+				break
+			}
+
+			slog.Default().Warn("failed to consume queue again", slog.String("queue", queue), slog.Any("error", err))
+			time.Sleep(minReconnectDelay)
+		}
+
+		slog.Default().Info("consumer resumed", slog.String("queue", queue))
+	}
+}
@@ -0,0 +1,115 @@
+package rabbit
+
+import (
+	"fmt"
+	"github.com/rabbitmq/amqp091-go"
+	"nft_service/infrastructure/messaging"
+)
+
+// declareRetryQueues declares the retry queues and the dead letter queue of the work queue. A retry queue holds
+// the messages for its delay and then dead-letters them back into the work queue.
+func declareRetryQueues(channel *amqp091.Channel, queue string, policy messaging.RetryPolicy) error {
+	for _, delay := range policy.Delays() {
+		_, err := channel.QueueDeclare(
+			messaging.RetryQueueName(queue, delay),
+			true,  // durable
+			false, // auto-delete
+			false, // exclusive
+			false, // no-wait
+			amqp091.Table{
+				"x-message-ttl":             delay.Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queue,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare retry queue: %w", err)
+		}
+	}
+
+	if _, err := channel.QueueDeclare(messaging.DeadLetterQueueName(queue), true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare dead letter queue: %w", err)
+	}
+
+	return nil
+}
+
+// Peek returns up to limit messages of the queue without removing them
+func (r *RabbitMQ) Peek(queue string, limit int) ([]messaging.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	msgs, err := r.get(queue, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// the channel is shared with the consumers, so the messages are returned one by one
+	if err := requeue(msgs); err != nil {
+		return nil, err
+	}
+
+	peeked := make([]messaging.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		peeked = append(peeked, messaging.NewMessage(msg.Body, messaging.Headers(msg.Headers), nil, nil))
+	}
+	return peeked, nil
+}
+
+// Replay moves up to limit messages of the dead letter queue back to the work queue with a fresh retry count
+// and returns how many were moved. A message is acked only after its copy is confirmed, if the connection
+// is lost in between the broker delivers it again and it is replayed twice.
+func (r *RabbitMQ) Replay(queue string, limit int) (int, error) {
+	// publish takes the lock itself, only fetching the dead-lettered messages holds it here
+	r.mu.Lock()
+	msgs, err := r.get(messaging.DeadLetterQueueName(queue), limit)
+	r.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, msg := range msgs {
+		if err := r.publish("", queue, true, amqp091.Publishing{ContentType: msg.ContentType, Body: msg.Body}); err != nil {
+			// the rest stays in the dead letter queue
+			requeue(msgs[i:])
+			return i, err
+		}
+		if err := msg.Ack(false); err != nil {
+			return i, fmt.Errorf("failed to ack replayed message: %w", err)
+		}
+	}
+
+	return len(msgs), nil
+}
+
+// get fetches up to limit messages of the queue, they have to be acked or requeued
+func (r *RabbitMQ) get(queue string, limit int) ([]amqp091.Delivery, error) {
+	var msgs []amqp091.Delivery
+
+	if r.channel == nil {
+		return nil, ErrNotConnected
+	}
+
+	for len(msgs) < limit {
+		msg, ok, err := r.channel.Get(queue, false)
+		if err != nil {
+			requeue(msgs)
+			return nil, fmt.Errorf("failed to get message: %w", err)
+		}
+		if !ok {
+			break
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+func requeue(msgs []amqp091.Delivery) error {
+	for _, msg := range msgs {
+		if err := msg.Nack(false, true); err != nil {
+			return fmt.Errorf("failed to requeue message: %w", err)
+		}
+	}
+	return nil
+}
@@ -8,10 +8,12 @@ import (
 	_ "net/http/pprof"
 	"nft_service/infrastructure/config"
 	"nft_service/infrastructure/database"
+	"nft_service/infrastructure/messaging"
 	"nft_service/infrastructure/rabbit"
 	"os"
 	"os/signal"
 	"runtime"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -43,14 +45,15 @@ func StartApp() {
 	}
 	defer db.Close()
 
-	mq, err := rabbit.NewRabbitMQ(cfg.AMQPURI)
+	mq, err := newQueue(cfg, db)
 	if err != nil {
-		l.Error("failed to connect to rabbitmq", slog.Any("error", err))
+		l.Error("failed to connect to "+cfg.QueueBackend, slog.Any("error", err))
 		os.Exit(1)
 	}
 	defer mq.Close()
 
-	server, err := setupServer(ctx, db, cfg, mq)
+	var wg sync.WaitGroup
+	server, err := setupServer(ctx, &wg, db, cfg, mq)
 	if err != nil {
 		l.Error("failed to setup server", slog.Any("error", err))
 		os.Exit(1)
@@ -78,7 +81,7 @@ func StartApp() {
 		}
 	}()
 
-	quit := make(chan os.Signal)
+	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
 	<-quit
@@ -92,6 +95,35 @@ func StartApp() {
 		l.Error("server shutdown:", slog.Any("error", err))
 	}
 
+	// the batches stop after their current chunk, the remaining items are resumed by the next run
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctxShutdown.Done():
+		l.Warn("background work did not stop in time")
+	}
+
 	<-ctxShutdown.Done()
 	l.Info("server exiting")
 }
+
+// newQueue creates the queue backend selected by QUEUE_BACKEND
+func newQueue(cfg *config.Config, db *database.DB) (messaging.Queue, error) {
+	switch cfg.QueueBackend {
+	case "postgres":
+		return messaging.NewPostgres(db.Conn), nil
+	case "memory":
+		return messaging.NewMemory(), nil
+	default:
+		mq, err := rabbit.NewRabbitMQ(cfg.AMQPURI)
+		if err != nil {
+			return nil, err
+		}
+		return mq, nil
+	}
+}
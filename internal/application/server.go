@@ -3,78 +3,227 @@ package application
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	ginprom "github.com/zsais/go-gin-prometheus"
 	"log/slog"
+	"nft_service/infrastructure/blockchain"
 	"nft_service/infrastructure/config"
 	"nft_service/infrastructure/database"
-	"nft_service/infrastructure/rabbit"
+	"nft_service/infrastructure/messaging"
 	"nft_service/infrastructure/utils"
 	"nft_service/internal/contract"
 	"nft_service/internal/controller"
+	"nft_service/internal/domain"
 	"nft_service/internal/persistence"
 	"nft_service/internal/service"
 	"nft_service/internal/worker"
 	"strings"
+	"sync"
+	"time"
 )
 
-func setupServer(ctx context.Context, db *database.DB, cfg *config.Config, mq *rabbit.RabbitMQ) (*gin.Engine, error) {
+// setupServer starts the background workers, wg is done once the ones that must not be cut off stopped
+func setupServer(ctx context.Context, wg *sync.WaitGroup, db *database.DB, cfg *config.Config, mq messaging.Queue,
+) (*gin.Engine, error) {
 
 	l := slog.Default()
 
 	tokenRepo := persistence.NewTokenRepo(db.Conn)
 	transferRepo := persistence.NewTransferRepo(db.Conn)
+	nonceRepo := persistence.NewNonceRepo(db.Conn)
+	chainTxRepo := persistence.NewChainTransactionRepo(db.Conn)
+	cursorRepo := persistence.NewCursorRepo(db.Conn)
+	mintBatchRepo := persistence.NewMintBatchRepo(db.Conn)
+	approvalRepo := persistence.NewApprovalRepo(db.Conn)
+	roleRepo := persistence.NewRoleRepo(db.Conn)
+	collectionRepo := persistence.NewCollectionRepo(db.Conn)
+	gasReportRepo := persistence.NewGasReportRepo(db.Conn)
+	outboxRepo := persistence.NewOutboxRepo(db.Conn)
+	eventRepo := persistence.NewEventRepo(db.Conn)
 
-	contractUrl, err := utils.GenerateInfuraURL(strings.ToLower(cfg.NetworkName), cfg.InfuraApiKey)
-	if err != nil {
-		return nil, errors.New("failed to generate Infura URL" + err.Error())
-	}
+	primaryChainID := cfg.PrimaryChain().ChainID
 
-	contractABI, err := utils.LoadABIFromFile(cfg.ContractABIPath)
-	if err != nil {
-		return nil, errors.New("failed to load contract ABI" + err.Error())
+	clients := make(map[int64]*blockchain.Client, len(cfg.Chains))
+	for _, chain := range cfg.Chains {
+		rpcURLs := chain.RPCURLs
+		if len(rpcURLs) == 0 {
+			// only the primary chain may be served by Infura, extra chains require their RPC URLs
+			infuraURL, err := utils.GenerateInfuraURL(strings.ToLower(cfg.NetworkName), cfg.InfuraApiKey)
+			if err != nil {
+				return nil, errors.New("failed to generate Infura URL" + err.Error())
+			}
+			rpcURLs = []string{infuraURL}
+		}
+
+		ethClient, err := blockchain.NewClient(rpcURLs, cfg.RPCMaxBlockLag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create rpc client of chain %d: %w", chain.ChainID, err)
+		}
+
+		if err := checkChainID(ctx, ethClient, chain.ChainID); err != nil {
+			return nil, err
+		}
+
+		go ethClient.StartHealthCheck(ctx, cfg.RPCHealthCheckInterval)
+
+		clients[chain.ChainID] = ethClient
 	}
 
-	contractService, err := contract.NewNFTContract(contractUrl, cfg, contractABI)
+	signer, err := contract.NewSigner(cfg)
 	if err != nil {
-		return nil, errors.New("failed to create contract service" + err.Error())
+		return nil, errors.New("failed to create transaction signer" + err.Error())
 	}
 
-	go contractService.StartCacheUpdater(ctx, cfg.CacheUpdateInterval)
+	contracts := contract.NewRegistry(clients, cfg, nonceRepo, chainTxRepo, signer)
 
-	tokenQueue, err := mq.DeclareQueue("token_queue")
-	if err != nil {
-		return nil, errors.New("failed to declare token queue" + err.Error())
-	}
+	var (
+		tokenQueues    = make(map[int64]string, len(cfg.Chains))
+		transferQueues = make(map[int64]string, len(cfg.Chains))
+		approvalQueues = make(map[int64]string, len(cfg.Chains))
+		workers        = make(map[int64]*worker.Worker, len(cfg.Chains))
+	)
 
-	transferQueue, err := mq.DeclareQueue("transfer_queue")
-	if err != nil {
-		return nil, errors.New("failed to declare transfer queue" + err.Error())
+	// receipt queues get retry queues with growing delays and a dead letter queue
+	var receiptQueues []string
+	declareReceiptQueue := func(name string) (string, error) {
+		if err := mq.Declare(name, cfg.ReceiptRetry); err != nil {
+			return "", err
+		}
+		receiptQueues = append(receiptQueues, name)
+		return name, nil
 	}
 
-	workerService, err := worker.NewWorker(contractUrl, mq, tokenQueue, transferQueue, tokenRepo, transferRepo, contractABI)
-	if err != nil {
-		return nil, errors.New("failed to create worker service" + err.Error())
+	for _, chain := range cfg.Chains {
+		isPrimary := chain.ChainID == primaryChainID
+
+		tokenQueue, err := declareReceiptQueue(queueName("token_queue", chain.ChainID, isPrimary))
+		if err != nil {
+			return nil, errors.New("failed to declare token queue" + err.Error())
+		}
+
+		transferQueue, err := declareReceiptQueue(queueName("transfer_queue", chain.ChainID, isPrimary))
+		if err != nil {
+			return nil, errors.New("failed to declare transfer queue" + err.Error())
+		}
+
+		approvalQueue, err := declareReceiptQueue(queueName("approval_queue", chain.ChainID, isPrimary))
+		if err != nil {
+			return nil, errors.New("failed to declare approval queue" + err.Error())
+		}
+
+		workerService, err := worker.NewWorker(chain.ChainID, clients[chain.ChainID], mq, tokenQueue, transferQueue,
+			approvalQueue, tokenRepo, transferRepo, approvalRepo, chainTxRepo, cursorRepo, roleRepo, contracts,
+			chain.Finality, cfg.ReceiptRetry)
+		if err != nil {
+			return nil, errors.New("failed to create worker service" + err.Error())
+		}
+
+		go func() {
+			if err := workerService.TokenUpdater(); err != nil {
+				l.Error("failed to update token queue" + err.Error())
+			}
+		}()
+
+		go func() {
+			if err := workerService.TransferStatusUpdater(); err != nil {
+				l.Error("failed to update transfer queue" + err.Error())
+			}
+		}()
+
+		go func() {
+			if err := workerService.ApprovalStatusUpdater(); err != nil {
+				l.Error("failed to update approval queue" + err.Error())
+			}
+		}()
+
+		go workerService.StuckTxMonitor(ctx, cfg.TxMonitorInterval, cfg.TxStuckAfter, cfg.TxMaxSpeedUps)
+		go workerService.Broadcaster(ctx, cfg.TxMonitorInterval)
+
+		// the market fees are estimated without the ceiling, the monitor reports what the balance really covers
+		balanceMonitor := contract.NewBalanceMonitor(clients[chain.ChainID],
+			contract.NewFeeStrategy(clients[chain.ChainID], chain.GasLimitMultiplier, nil), chain.ChainID,
+			signer.Address(), chain.LowBalance, cfg.LowBalanceWebhookURL)
+		go balanceMonitor.Start(ctx, cfg.BalanceCheckInterval)
+
+		tokenQueues[chain.ChainID] = tokenQueue
+		transferQueues[chain.ChainID] = transferQueue
+		approvalQueues[chain.ChainID] = approvalQueue
+		workers[chain.ChainID] = workerService
 	}
 
-	go func() {
-		if err := workerService.TokenUpdater(); err != nil {
-			l.Error("failed to update token queue" + err.Error())
+	outboxRelay := worker.NewOutboxRelay(outboxRepo, mq, map[string]map[int64]string{
+		domain.ChainTxKindMint:     tokenQueues,
+		domain.ChainTxKindTransfer: transferQueues,
+		domain.ChainTxKindApproval: approvalQueues,
+	})
+	go outboxRelay.Start(ctx, cfg.OutboxRelayInterval)
+
+	// the events stay in the events table when the queue backend has no exchanges
+	if publisher, ok := mq.(messaging.TopicPublisher); ok {
+		if err := publisher.DeclareExchange(cfg.EventsExchange); err != nil {
+			return nil, errors.New("failed to declare events exchange " + err.Error())
 		}
-	}()
+		go worker.NewEventRelay(eventRepo, publisher, cfg.EventsExchange).Start(ctx, cfg.OutboxRelayInterval)
+	} else {
+		l.Warn("domain events are not published by the queue backend", slog.String("backend", cfg.QueueBackend))
+	}
 
-	go func() {
-		if err := workerService.TransferStatusUpdater(); err != nil {
-			l.Error("failed to update transfer queue" + err.Error())
+	indexerConfig := func(collection *domain.Collection) worker.IndexerConfig {
+		chain, _ := cfg.Chain(collection.ChainID)
+		return worker.IndexerConfig{
+			CollectionID:    collection.ID,
+			ContractAddress: common.HexToAddress(collection.Address),
+			StartBlock:      chain.IndexerStartBlock,
+			BlockRange:      cfg.IndexerBlockRange,
+			PollInterval:    cfg.IndexerPollInterval,
 		}
-	}()
+	}
+
+	collectionService := service.NewCollectionService(collectionRepo, contracts, cfg.MinterRole, cfg.UserAddress,
+		func(collection *domain.Collection, nft *contract.NFTContract) {
+			go nft.StartCacheUpdater(ctx, cfg.CacheUpdateInterval)
+			go workers[collection.ChainID].TransferIndexer(ctx, indexerConfig(collection))
+			go workers[collection.ChainID].RoleIndexer(ctx, indexerConfig(collection), worker.RoleSeed{
+				Contract: nft,
+				Roles:    []string{contract.DefaultAdminRole, cfg.MinterRole},
+				Accounts: []string{cfg.UserAddress},
+			})
+		},
+	)
+
+	defaultCollection := &domain.Collection{
+		Address: cfg.ContractAddress,
+		ChainID: primaryChainID,
+		ABIRef:  cfg.ContractABIPath,
+		Name:    "default",
+	}
+	if err := collectionService.Load(defaultCollection); err != nil {
+		return nil, errors.New("failed to load collections " + err.Error())
+	}
 
-	tokenService := service.NewTokenService(tokenRepo, contractService, mq, tokenQueue)
-	transferService := service.NewTransferService(transferRepo, contractService, mq, transferQueue)
+	tokenService := service.NewTokenService(tokenRepo, contracts)
+	transferService := service.NewTransferService(transferRepo, contracts)
+	chainService := service.NewChainService(contracts)
+	approvalService := service.NewApprovalService(approvalRepo, contracts)
+	roleService := service.NewRoleService(roleRepo, contracts)
+	reportService := service.NewReportService(gasReportRepo, primaryChainID)
+	deadLetterService := service.NewDeadLetterService(mq, receiptQueues)
+	batchService := service.NewBatchService(ctx, wg, mintBatchRepo, contracts, cfg.BatchMaxItems, cfg.BatchChunkSize)
+	go batchService.Start(cfg.TxMonitorInterval)
 	tokenHandler := controller.NewTokenHandler(tokenService)
 	transferHandler := controller.NewTransferHandler(transferService)
+	chainHandler := controller.NewChainHandler(chainService)
+	batchHandler := controller.NewBatchHandler(batchService)
+	approvalHandler := controller.NewApprovalHandler(approvalService)
+	roleHandler := controller.NewRoleHandler(roleService)
+	collectionHandler := controller.NewCollectionHandler(collectionService)
+	reportHandler := controller.NewReportHandler(reportService)
+	deadLetterHandler := controller.NewDeadLetterHandler(deadLetterService)
+	healthHandler := controller.NewHealthHandler(map[string]controller.HealthChecker{cfg.QueueBackend: mq})
 
 	r := gin.New()
 	r.Use(gin.Recovery())
@@ -84,18 +233,89 @@ func setupServer(ctx context.Context, db *database.DB, cfg *config.Config, mq *r
 	prom.Use(r)
 
 	r.GET("/api/ping", controller.Ping)
+	r.GET("/api/health", healthHandler.Health)
 	r.GET("/api/docs/spec", func(c *gin.Context) {
 		c.File("./docs/swagger.json")
 	})
 	r.GET("/api/docs/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/api/docs/spec")))
 
-	r.POST("/api/tokens/create", tokenHandler.Create)
-	r.GET("/api/tokens/list", tokenHandler.List)
-	r.GET("/api/tokens/total_supply", tokenHandler.Total)
-	r.GET("/api/tokens/total_supply_exact", tokenHandler.ExactTotal)
+	r.GET("/api/collections/list", collectionHandler.List)
+
+	adminOnly := controller.AdminMiddleware(cfg.AdminToken)
+
+	// the routes without a collection id are served by the default collection
+	for _, group := range []*gin.RouterGroup{
+		r.Group("/api", controller.CollectionMiddleware(collectionService)),
+		r.Group("/api/collections/:collection_id", controller.CollectionMiddleware(collectionService)),
+	} {
+		group.POST("/tokens/create", tokenHandler.Create)
+		group.GET("/tokens/list", tokenHandler.List)
+		group.GET("/tokens/total_supply", tokenHandler.Total)
+		group.GET("/tokens/total_supply_exact", tokenHandler.ExactTotal)
+		group.POST("/tokens/batch", batchHandler.Create)
+		group.GET("/tokens/batch/:id", batchHandler.Get)
+
+		group.POST("/transfers/create", transferHandler.Create)
+		group.GET("/transfers/list", transferHandler.List)
+
+		group.GET("/chain/info", chainHandler.Info)
+		group.GET("/chain/tokens/:id/owner", chainHandler.Owner)
+		group.GET("/chain/tokens/:id/uri", chainHandler.TokenURI)
+		group.GET("/chain/indexes/:index/token", chainHandler.TokenByIndex)
+		group.GET("/chain/owners/:address/balance", chainHandler.Balance)
+		group.GET("/chain/owners/:address/tokens/:index", chainHandler.TokenOfOwnerByIndex)
+		group.GET("/chain/hash/:unique_hash", chainHandler.HashToID)
+		group.GET("/chain/tokens/:id/approved", chainHandler.GetApproved)
+		group.GET("/chain/owners/:address/operators/:operator", chainHandler.IsApprovedForAll)
+
+		// approvals hand the tokens of the service wallet to other addresses
+		group.POST("/approvals/approve", adminOnly, approvalHandler.Approve)
+		group.POST("/approvals/operator", adminOnly, approvalHandler.SetApprovalForAll)
+		group.GET("/approvals/list", adminOnly, approvalHandler.List)
+	}
+
+	r.GET("/api/reports/gas", reportHandler.Gas)
 
-	r.POST("/api/transfers/create", transferHandler.Create)
-	r.GET("/api/transfers/list", transferHandler.List)
+	admin := r.Group("/api/admin", adminOnly)
+
+	// the role routes without a collection id administer the default collection
+	for _, group := range []*gin.RouterGroup{
+		admin.Group("", controller.CollectionMiddleware(collectionService)),
+		admin.Group("/collections/:collection_id", controller.CollectionMiddleware(collectionService)),
+	} {
+		group.GET("/roles/:role/members", roleHandler.Members)
+		group.GET("/roles/:role/members/:address", roleHandler.HasRole)
+		group.POST("/roles/:role/grant", roleHandler.Grant)
+		group.POST("/roles/:role/revoke", roleHandler.Revoke)
+	}
+	admin.POST("/collections", collectionHandler.Create)
+	admin.GET("/dlq/:queue", deadLetterHandler.List)
+	admin.POST("/dlq/:queue/replay", deadLetterHandler.Replay)
 
 	return r, nil
 }
+
+// queueName keeps the queue names of the primary chain, the queues of other chains get the chain id as suffix
+func queueName(name string, chainID int64, isPrimary bool) string {
+	if isPrimary {
+		return name
+	}
+	return fmt.Sprintf("%s.%d", name, chainID)
+}
+
+// checkChainID makes sure the RPC endpoints of a chain serve the configured chain,
+// transactions signed for one chain must never be sent to another one
+func checkChainID(ctx context.Context, client *blockchain.Client, chainID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	actual, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain id of chain %d: %w", chainID, err)
+	}
+	if actual.Int64() != chainID {
+		return fmt.Errorf("rpc endpoints of chain %d serve chain %s", chainID, actual)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package contract
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"nft_service/internal/contract/bindings"
+	"strings"
+)
+
+// Topics of the contract events the indexers filter by, CheckABI verifies them against the bindings
+var (
+	TransferTopic    = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	RoleGrantedTopic = crypto.Keccak256Hash([]byte("RoleGranted(bytes32,address,address)"))
+	RoleRevokedTopic = crypto.Keccak256Hash([]byte("RoleRevoked(bytes32,address,address)"))
+)
+
+// indexedEvents maps the events of the bindings to the topics the indexers use for them
+var indexedEvents = map[string]common.Hash{
+	"Transfer":    TransferTopic,
+	"RoleGranted": RoleGrantedTopic,
+	"RoleRevoked": RoleRevokedTopic,
+}
+
+// calldata returns the input of a transaction built by the generated bindings without signing or sending it,
+// so the arguments are type checked while simulation, fees and nonces stay with sendTransaction
+func calldata(transact func(opts *bind.TransactOpts) (*types.Transaction, error)) ([]byte, error) {
+	tx, err := transact(&bind.TransactOpts{
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+		Nonce:     new(big.Int),
+		GasLimit:  1,
+		GasTipCap: new(big.Int),
+		GasFeeCap: new(big.Int),
+		NoSend:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tx.Data(), nil
+}
+
+// CheckABI verifies that every method and event of the generated bindings exists with the same signature
+// in the ABI, so a deployment pointing at an ABI the bindings were not generated from fails at startup
+func CheckABI(contractABI string) error {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return fmt.Errorf("failed to parse contract ABI: %w", err)
+	}
+
+	generated, err := bindings.NFTMetaData.GetAbi()
+	if err != nil {
+		return fmt.Errorf("failed to parse bindings ABI: %w", err)
+	}
+
+	for name, method := range generated.Methods {
+		if m, ok := parsed.Methods[name]; !ok || m.Sig != method.Sig {
+			return fmt.Errorf("method %s of the bindings is missing in the ABI, regenerate the bindings", method.Sig)
+		}
+	}
+
+	for name, event := range generated.Events {
+		if e, ok := parsed.Events[name]; !ok || e.ID != event.ID {
+			return fmt.Errorf("event %s of the bindings is missing in the ABI, regenerate the bindings", event.Sig)
+		}
+	}
+
+	for name, topic := range indexedEvents {
+		if e, ok := generated.Events[name]; !ok || e.ID != topic {
+			return fmt.Errorf("event %s of the indexers is missing in the bindings", name)
+		}
+	}
+
+	return nil
+}
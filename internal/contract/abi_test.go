@@ -0,0 +1,64 @@
+package contract
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"nft_service/internal/contract/bindings"
+	"os"
+	"testing"
+)
+
+func TestCheckABI(t *testing.T) {
+	contractABI, err := os.ReadFile("../../contract_abi.json")
+	require.NoError(t, err)
+
+	assert.NoError(t, CheckABI(string(contractABI)))
+	assert.Error(t, CheckABI("not json"))
+
+	// an ABI without a method the bindings call must be rejected
+	var entries []map[string]any
+	require.NoError(t, json.Unmarshal(contractABI, &entries))
+
+	var withoutMint []map[string]any
+	for _, entry := range entries {
+		if entry["name"] != "mint" {
+			withoutMint = append(withoutMint, entry)
+		}
+	}
+
+	changed, err := json.Marshal(withoutMint)
+	require.NoError(t, err)
+	assert.ErrorContains(t, CheckABI(string(changed)), "mint")
+}
+
+func TestIndexedEventTopics(t *testing.T) {
+	parsed, err := bindings.NFTMetaData.GetAbi()
+	require.NoError(t, err)
+
+	for name, topic := range indexedEvents {
+		assert.Equal(t, parsed.Events[name].ID, topic, name)
+	}
+}
+
+func TestCalldata(t *testing.T) {
+	nft, err := bindings.NewNFT(common.Address{}, nil)
+	require.NoError(t, err)
+
+	parsed, err := bindings.NFTMetaData.GetAbi()
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0x399c1448e0F34aB3722e3aFDd21301Ca6cFF4c4a")
+	expected, err := parsed.Pack("approve", to, big.NewInt(7))
+	require.NoError(t, err)
+
+	data, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return nft.Approve(opts, to, big.NewInt(7))
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expected, data)
+}
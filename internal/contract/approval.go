@@ -0,0 +1,90 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"log/slog"
+	"math/big"
+	"nft_service/internal/domain"
+	"time"
+)
+
+// Approve sends approve(operator, tokenId) from the service wallet. Approving the zero address revokes the approval.
+func (m *NFTContract) Approve(approval *domain.Approval) (*domain.Approval, error) {
+	tokenID, ok := new(big.Int).SetString(approval.TokenID, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid token id")
+	}
+
+	txData, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return m.nft.Approve(opts, common.HexToAddress(approval.Operator), tokenID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack approve data: %w", err)
+	}
+
+	approval.Approved = common.HexToAddress(approval.Operator) != (common.Address{})
+
+	return m.sendApproval(approval, txData)
+}
+
+// SetApprovalForAll sends setApprovalForAll(operator, approved) from the service wallet
+func (m *NFTContract) SetApprovalForAll(approval *domain.Approval) (*domain.Approval, error) {
+	txData, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return m.nft.SetApprovalForAll(opts, common.HexToAddress(approval.Operator), approval.Approved)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack setApprovalForAll data: %w", err)
+	}
+
+	return m.sendApproval(approval, txData)
+}
+
+func (m *NFTContract) sendApproval(approval *domain.Approval, txData []byte) (*domain.Approval, error) {
+	var (
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		l           = slog.Default()
+		startTime   = time.Now()
+	)
+	defer cancel()
+
+	approval.Status = domain.ApprovalStatusPending
+
+	signedTx, err := m.sendTransaction(ctx, domain.ChainTxKindApproval, txData, &domain.TxIntent{Approval: approval})
+	if err != nil {
+		return nil, err
+	}
+
+	approval.TxHash = signedTx.Hash().Hex()
+
+	l.Info("approval transaction sent",
+		slog.String("kind", approval.Kind),
+		slog.String("tx_hash", approval.TxHash),
+		slog.Uint64("nonce", signedTx.Nonce()),
+		slog.Float64("latency", time.Since(startTime).Seconds()),
+	)
+
+	return approval, nil
+}
+
+// GetApproved returns the address approved for the token. Approvals change through this service,
+// so the result is not cached.
+func (m *NFTContract) GetApproved(tokenID *big.Int) (string, error) {
+	approved, err := view("getApproved", func(opts *bind.CallOpts) (common.Address, error) {
+		return m.nft.GetApproved(opts, tokenID)
+	})
+	if err != nil {
+		return "", err
+	}
+	return approved.Hex(), nil
+}
+
+// IsApprovedForAll reports whether the operator may manage all tokens of the owner, not cached like GetApproved
+func (m *NFTContract) IsApprovedForAll(owner, operator string) (bool, error) {
+	return view("isApprovedForAll", func(opts *bind.CallOpts) (bool, error) {
+		return m.nft.IsApprovedForAll(opts, common.HexToAddress(owner), common.HexToAddress(operator))
+	})
+}
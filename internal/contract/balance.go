@@ -0,0 +1,227 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"nft_service/internal/domain"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrInsufficientFunds is returned before broadcasting a transaction the service wallet cannot pay for
+var ErrInsufficientFunds = errors.New("service wallet balance is insufficient")
+
+// typicalGas is the gas a mint and a transfer of the contract usually use, it estimates how many
+// operations the wallet balance covers
+var typicalGas = map[string]uint64{
+	domain.ChainTxKindMint:     150_000,
+	domain.ChainTxKindTransfer: 65_000,
+}
+
+var (
+	walletBalance = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "wallet_balance_wei",
+		Help: "Balance of the service wallet by chain.",
+	}, []string{"chain_id"})
+
+	walletCoveredOperations = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "wallet_covered_operations",
+		Help: "Number of mints or transfers the service wallet balance covers at the current fees.",
+	}, []string{"chain_id", "operation"})
+)
+
+// balanceSource is the part of the ethereum client the balance checks rely on
+type balanceSource interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// checkFunds returns ErrInsufficientFunds when the balance of the address is below the cost in wei
+func checkFunds(ctx context.Context, client balanceSource, address common.Address, cost *big.Int) error {
+	balance, err := client.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+
+	if balance.Cmp(cost) < 0 {
+		return fmt.Errorf("%w: balance %s wei, the transaction may cost up to %s wei", ErrInsufficientFunds, balance, cost)
+	}
+
+	return nil
+}
+
+// maxCost is the most a transaction signed with the fees can cost
+func maxCost(fees *Fees) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(fees.GasLimit), fees.GasFeeCap)
+}
+
+// BalanceAlert is posted to the low balance webhook
+type BalanceAlert struct {
+	ChainID          int64  `json:"chain_id"`
+	Address          string `json:"address"`
+	BalanceWei       string `json:"balance_wei"`
+	ThresholdWei     string `json:"threshold_wei"`
+	MintsCovered     uint64 `json:"mints_covered"`
+	TransfersCovered uint64 `json:"transfers_covered"`
+}
+
+// BalanceMonitor tracks the balance of the service wallet on one chain. It is alerted once when the balance
+// drops below the threshold and again only after the balance recovered. A failed webhook call is retried
+// on the next check while the balance stays low.
+type BalanceMonitor struct {
+	client     balanceSource
+	fees       *FeeStrategy
+	chainID    int64
+	address    common.Address
+	threshold  *big.Int
+	webhookURL string
+	httpClient *http.Client
+	low        bool
+	notified   bool // the webhook accepted the alert of the current low balance
+	mu         sync.Mutex
+}
+
+// NewBalanceMonitor creates the monitor, an empty webhookURL only logs the alerts
+func NewBalanceMonitor(client balanceSource, fees *FeeStrategy, chainID int64, address common.Address,
+	threshold *big.Int, webhookURL string,
+) *BalanceMonitor {
+	return &BalanceMonitor{
+		client:     client,
+		fees:       fees,
+		chainID:    chainID,
+		address:    address,
+		threshold:  threshold,
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (b *BalanceMonitor) Start(ctx context.Context, interval time.Duration) {
+	l := slog.Default().With(slog.Int64("chain_id", b.chainID))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.check(ctx); err != nil {
+			l.Error("failed to check wallet balance", slog.Any("error", err))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			l.Info("balance monitor stopped")
+			return
+		}
+	}
+}
+
+func (b *BalanceMonitor) check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	balance, err := b.client.BalanceAt(ctx, b.address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+
+	chainLabel := strconv.FormatInt(b.chainID, 10)
+	balanceWei, _ := new(big.Float).SetInt(balance).Float64()
+	walletBalance.WithLabelValues(chainLabel).Set(balanceWei)
+
+	fees, err := b.fees.Market(ctx)
+	if err != nil {
+		return err
+	}
+
+	alert := &BalanceAlert{
+		ChainID:          b.chainID,
+		Address:          b.address.Hex(),
+		BalanceWei:       balance.String(),
+		ThresholdWei:     b.threshold.String(),
+		MintsCovered:     covered(balance, fees, domain.ChainTxKindMint),
+		TransfersCovered: covered(balance, fees, domain.ChainTxKindTransfer),
+	}
+	walletCoveredOperations.WithLabelValues(chainLabel, domain.ChainTxKindMint).Set(float64(alert.MintsCovered))
+	walletCoveredOperations.WithLabelValues(chainLabel, domain.ChainTxKindTransfer).Set(float64(alert.TransfersCovered))
+
+	b.mu.Lock()
+	wasLow := b.low
+	b.low = balance.Cmp(b.threshold) < 0
+	if !b.low {
+		b.notified = false
+	}
+	low, notified := b.low, b.notified
+	b.mu.Unlock()
+
+	if !low || notified {
+		return nil
+	}
+
+	if !wasLow {
+		slog.Default().Warn("service wallet balance is low",
+			slog.Int64("chain_id", b.chainID),
+			slog.String("address", alert.Address),
+			slog.String("balance_wei", alert.BalanceWei),
+			slog.Uint64("mints_covered", alert.MintsCovered),
+			slog.Uint64("transfers_covered", alert.TransfersCovered),
+		)
+	}
+
+	if err := b.notify(ctx, alert); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.notified = true
+	b.mu.Unlock()
+
+	return nil
+}
+
+// notify posts the alert to the webhook
+func (b *BalanceMonitor) notify(ctx context.Context, alert *BalanceAlert) error {
+	if b.webhookURL == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create low balance webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call low balance webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("low balance webhook answered %s", resp.Status)
+	}
+
+	return nil
+}
+
+// covered is how many operations of the kind the balance pays for at the fee cap
+func covered(balance *big.Int, fees *Fees, kind string) uint64 {
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(typicalGas[kind]), fees.GasFeeCap)
+	if cost.Sign() == 0 {
+		return 0
+	}
+	return new(big.Int).Div(balance, cost).Uint64()
+}
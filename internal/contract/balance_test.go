@@ -0,0 +1,80 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeBalanceSource struct {
+	balance *big.Int
+}
+
+func (f *fakeBalanceSource) BalanceAt(_ context.Context, _ common.Address, _ *big.Int) (*big.Int, error) {
+	return f.balance, nil
+}
+
+func TestCheckFunds(t *testing.T) {
+	source := &fakeBalanceSource{balance: big.NewInt(1000)}
+
+	assert.NoError(t, checkFunds(context.Background(), source, testSigner, big.NewInt(1000)))
+	assert.ErrorIs(t, checkFunds(context.Background(), source, testSigner, big.NewInt(1001)), ErrInsufficientFunds)
+	assert.Equal(t, big.NewInt(2200000), maxCost(&Fees{GasLimit: 100000, GasFeeCap: big.NewInt(22)}))
+}
+
+func TestBalanceMonitor_AlertsOnceBelowThreshold(t *testing.T) {
+	var alerts []BalanceAlert
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert BalanceAlert
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		alerts = append(alerts, alert)
+	}))
+	defer webhook.Close()
+
+	balances := &fakeBalanceSource{balance: big.NewInt(3_000_000_000)}
+	fees := NewFeeStrategy(&fakeFeeSource{tipCap: big.NewInt(2), baseFee: big.NewInt(9)}, 1, nil)
+	monitor := NewBalanceMonitor(balances, fees, testChainID, testSigner, big.NewInt(1_000_000_000), webhook.URL)
+
+	require.NoError(t, monitor.check(context.Background()))
+	assert.Empty(t, alerts, "balance above the threshold must not alert")
+
+	balances.balance = big.NewInt(600_000_000)
+	require.NoError(t, monitor.check(context.Background()))
+	require.NoError(t, monitor.check(context.Background()))
+	require.Len(t, alerts, 1, "a low balance must alert once")
+	assert.Equal(t, "600000000", alerts[0].BalanceWei)
+	// fee cap 2 * 9 + 2 = 20 wei, a mint is 150000 gas
+	assert.Equal(t, uint64(200), alerts[0].MintsCovered)
+
+	balances.balance = big.NewInt(3_000_000_000)
+	require.NoError(t, monitor.check(context.Background()))
+	balances.balance = big.NewInt(600_000_000)
+	require.NoError(t, monitor.check(context.Background()))
+	assert.Len(t, alerts, 2, "a balance dropping again after a recovery must alert again")
+}
+
+func TestBalanceMonitor_RetriesFailedAlert(t *testing.T) {
+	var calls int
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer webhook.Close()
+
+	balances := &fakeBalanceSource{balance: big.NewInt(600_000_000)}
+	fees := NewFeeStrategy(&fakeFeeSource{tipCap: big.NewInt(2), baseFee: big.NewInt(9)}, 1, nil)
+	monitor := NewBalanceMonitor(balances, fees, testChainID, testSigner, big.NewInt(1_000_000_000), webhook.URL)
+
+	assert.Error(t, monitor.check(context.Background()))
+	require.NoError(t, monitor.check(context.Background()))
+	require.NoError(t, monitor.check(context.Background()))
+	assert.Equal(t, 2, calls, "a failed alert must be sent again until the webhook accepts it")
+}
@@ -0,0 +1,191 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/params"
+	"log/slog"
+	"math/big"
+	"nft_service/internal/domain"
+	"time"
+)
+
+type batchCall struct {
+	index    int
+	data     []byte
+	gasLimit uint64
+}
+
+// MintBatch mints the tokens with contiguous nonces and one fee query. Every token is simulated first and
+// only the ones that would succeed get a nonce. The returned errors match the tokens by index, a nil error
+// means the token was stored with its signed mint transaction and the TxHash of the token is set.
+func (m *NFTContract) MintBatch(tokens []*domain.Token) []error {
+	var (
+		ctx, cancel = context.WithTimeout(context.Background(), 2*time.Minute)
+		l           = slog.Default()
+		startTime   = time.Now()
+		errs        = make([]error, len(tokens))
+		fromAddress = m.signer.Address()
+		toAddress   = m.address
+		calls       []batchCall
+	)
+	defer cancel()
+
+	for i, token := range tokens {
+		txData, err := m.mintCalldata(token)
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to pack mint transaction data: %w", err)
+			continue
+		}
+
+		if err := m.simulate(ctx, txData); err != nil {
+			errs[i] = err
+			continue
+		}
+
+		gasLimit, err := m.fees.GasLimit(ctx, ethereum.CallMsg{
+			From:  fromAddress,
+			To:    &toAddress,
+			Value: big.NewInt(0),
+			Data:  txData,
+		})
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		calls = append(calls, batchCall{index: i, data: txData, gasLimit: gasLimit})
+	}
+
+	if len(calls) == 0 {
+		return errs
+	}
+
+	fees, err := m.fees.Market(ctx)
+	if err != nil {
+		return failCalls(errs, calls, err)
+	}
+
+	var gas uint64
+	for _, call := range calls {
+		gas += call.gasLimit
+	}
+	if err := checkFunds(ctx, m.client, fromAddress, maxCost(&Fees{GasLimit: gas, GasFeeCap: fees.GasFeeCap})); err != nil {
+		return failCalls(errs, calls, err)
+	}
+
+	first, err := m.nonces.Reserve(ctx, fromAddress, uint64(len(calls)))
+	if err != nil {
+		return failCalls(errs, calls, err)
+	}
+
+	for i, call := range calls {
+		nonce := first + uint64(i)
+		callFees := &Fees{GasLimit: call.gasLimit, GasTipCap: fees.GasTipCap, GasFeeCap: fees.GasFeeCap}
+
+		signedTx, err := m.signTransaction(ctx, nonce, toAddress, call.data, callFees)
+		if err != nil {
+			errs[call.index] = err
+			m.fillNonce(ctx, nonce, fees)
+			continue
+		}
+
+		token := tokens[call.index]
+		record, err := m.storeTransaction(&domain.ChainTransaction{
+			Kind:   domain.ChainTxKindMint,
+			Intent: &domain.TxIntent{Token: token},
+		}, signedTx)
+		if err != nil {
+			errs[call.index] = err
+			m.fillNonce(ctx, nonce, fees)
+			continue
+		}
+
+		err = m.client.SendTransaction(ctx, signedTx)
+		if err == nil {
+			m.markBroadcast(record)
+			continue
+		}
+
+		if IsPermanentSendError(err) {
+			errs[call.index] = fmt.Errorf("transaction rejected by the node: %w", err)
+			if discardErr := m.txRepo.Discard(record.TxHash); discardErr != nil {
+				l.Error("failed to discard rejected transaction", slog.String("tx_hash", record.TxHash),
+					slog.Any("error", discardErr))
+				continue
+			}
+			m.fillNonce(ctx, nonce, fees)
+			continue
+		}
+
+		if !isNonceError(err) {
+			// the token is stored with its transaction, the broadcaster retries it
+			l.Warn("batch transaction stored but not broadcast, the broadcaster will retry",
+				slog.String("tx_hash", record.TxHash),
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		errs[call.index] = fmt.Errorf("failed to send transaction: %w", err)
+		if discardErr := m.txRepo.Discard(record.TxHash); discardErr != nil {
+			l.Error("failed to discard rejected transaction", slog.String("tx_hash", record.TxHash),
+				slog.Any("error", discardErr))
+		}
+
+		// the reserved nonces are taken by someone else, release the rest of the reservation
+		m.nonces.Resync(fromAddress)
+		return failCalls(errs, calls[i+1:], fmt.Errorf("batch aborted after nonce conflict: %w", err))
+	}
+
+	l.Info("batch mint transactions sent",
+		slog.Int("tokens", len(tokens)),
+		slog.Int("sent", len(calls)),
+		slog.Uint64("first_nonce", first),
+		slog.Float64("latency", time.Since(startTime).Seconds()),
+	)
+
+	return errs
+}
+
+// fillNonce sends a zero-value self-transfer with the nonce of a reserved transaction that could not be sent,
+// so the transactions with the following nonces are not stuck behind the gap
+func (m *NFTContract) fillNonce(ctx context.Context, nonce uint64, fees *Fees) {
+	l := slog.Default()
+
+	noopFees := &Fees{GasLimit: params.TxGas, GasTipCap: fees.GasTipCap, GasFeeCap: fees.GasFeeCap}
+
+	var record *domain.ChainTransaction
+
+	signedTx, err := m.signTransaction(ctx, nonce, m.signer.Address(), nil, noopFees)
+	if err == nil {
+		record, err = m.storeTransaction(&domain.ChainTransaction{Kind: domain.ChainTxKindNonceFill, IsCancel: true}, signedTx)
+	}
+	if err != nil {
+		// the next single transaction takes the pending nonce of the chain, which is the gap
+		m.nonces.Resync(m.signer.Address())
+		l.Error("failed to fill nonce gap", slog.Uint64("nonce", nonce), slog.Any("error", err))
+		return
+	}
+
+	if err := m.client.SendTransaction(ctx, signedTx); err != nil {
+		// the no-op is stored, the broadcaster retries it
+		l.Warn("no-op transaction stored but not broadcast", slog.Uint64("nonce", nonce), slog.Any("error", err))
+		return
+	}
+
+	m.markBroadcast(record)
+
+	l.Warn("nonce gap filled with no-op transaction",
+		slog.Uint64("nonce", nonce),
+		slog.String("tx_hash", signedTx.Hash().Hex()),
+	)
+}
+
+func failCalls(errs []error, calls []batchCall, err error) []error {
+	for _, call := range calls {
+		errs[call.index] = err
+	}
+	return errs
+}
@@ -0,0 +1,5 @@
+// Package bindings holds the typed contract bindings generated from contract_abi.json.
+// Run go generate ./internal/contract/bindings after the ABI changes.
+package bindings
+
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --abi ../../../contract_abi.json --pkg bindings --type NFT --out nft.go
@@ -0,0 +1,6 @@
+//go:build tools
+
+package bindings
+
+// keeps the abigen version pinned by go.mod
+import _ "github.com/ethereum/go-ethereum/cmd/abigen"
@@ -0,0 +1,56 @@
+package contract
+
+import (
+	"sync"
+	"time"
+)
+
+// maxViewCacheEntries is the size above which expired entries are purged on insert
+const maxViewCacheEntries = 10000
+
+// viewCache keeps view call results for a short time per call and arguments
+type viewCache struct {
+	ttl     time.Duration
+	entries map[string]viewCacheEntry
+	mu      sync.RWMutex
+}
+
+type viewCacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+func newViewCache(ttl time.Duration) *viewCache {
+	return &viewCache{
+		ttl:     ttl,
+		entries: make(map[string]viewCacheEntry),
+	}
+}
+
+func (c *viewCache) get(key string) (any, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *viewCache) set(key string, value any) {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxViewCacheEntries {
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+
+	c.entries[key] = viewCacheEntry{value: value, expires: now.Add(c.ttl)}
+}
@@ -0,0 +1,23 @@
+package contract
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestViewCache(t *testing.T) {
+	cache := newViewCache(50 * time.Millisecond)
+
+	_, ok := cache.get("ownerOf:1")
+	assert.False(t, ok)
+
+	cache.set("ownerOf:1", []any{"0x01"})
+	value, ok := cache.get("ownerOf:1")
+	assert.True(t, ok)
+	assert.Equal(t, []any{"0x01"}, value)
+
+	time.Sleep(60 * time.Millisecond)
+	_, ok = cache.get("ownerOf:1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,168 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math"
+	"math/big"
+)
+
+// ErrFeeCeilingExceeded is returned when the current network fees are above the configured max fee per gas
+var ErrFeeCeilingExceeded = errors.New("network fee exceeds configured ceiling")
+
+// feeSource is the part of the ethereum client the fee strategy relies on
+type feeSource interface {
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Fees is the gas limit and EIP-1559 fee caps to sign a transaction with
+type Fees struct {
+	GasLimit  uint64
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+// FeeStrategy estimates the gas limit and fee caps of a transaction
+type FeeStrategy struct {
+	client        feeSource
+	gasMultiplier float64
+	maxFeePerGas  *big.Int
+}
+
+// NewFeeStrategy creates a fee strategy. The estimated gas is multiplied by gasMultiplier,
+// maxFeePerGas is the ceiling for the fee cap, nil means no ceiling.
+func NewFeeStrategy(client feeSource, gasMultiplier float64, maxFeePerGas *big.Int) *FeeStrategy {
+	return &FeeStrategy{
+		client:        client,
+		gasMultiplier: gasMultiplier,
+		maxFeePerGas:  maxFeePerGas,
+	}
+}
+
+// Estimate returns the fees for the call. The fee cap leaves room for the base fee
+// to double before the transaction is included, but never goes above the ceiling.
+func (f *FeeStrategy) Estimate(ctx context.Context, msg ethereum.CallMsg) (*Fees, error) {
+	gasLimit, err := f.GasLimit(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := f.Market(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fees.GasLimit = gasLimit
+
+	return fees, nil
+}
+
+// GasLimit estimates the gas of the call and applies the safety multiplier
+func (f *FeeStrategy) GasLimit(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	gas, err := f.client.EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	return uint64(math.Ceil(float64(gas) * f.gasMultiplier)), nil
+}
+
+// Market returns the fee caps for the current network fees without a gas limit,
+// so several transactions can be priced with one query
+func (f *FeeStrategy) Market(ctx context.Context) (*Fees, error) {
+	tipCap, err := f.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas tip cap: %w", err)
+	}
+
+	header, err := f.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+
+	if header.BaseFee == nil {
+		return nil, errors.New("chain does not support EIP-1559 base fee")
+	}
+
+	minFeeCap := new(big.Int).Add(header.BaseFee, tipCap)
+	feeCap := new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), tipCap)
+
+	if f.maxFeePerGas != nil {
+		if minFeeCap.Cmp(f.maxFeePerGas) > 0 {
+			return nil, fmt.Errorf("%w: base fee %s + tip %s > max fee per gas %s wei",
+				ErrFeeCeilingExceeded, header.BaseFee, tipCap, f.maxFeePerGas)
+		}
+		if feeCap.Cmp(f.maxFeePerGas) > 0 {
+			feeCap = new(big.Int).Set(f.maxFeePerGas)
+		}
+	}
+
+	return &Fees{
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+	}, nil
+}
+
+// replacementBumpPercent is how much the fee caps of a stuck transaction are raised when it is replaced.
+// Nodes reject replacements with less than a 10% bump.
+const replacementBumpPercent = 20
+
+// Bump returns the fees for a replacement of a transaction signed with prev: at least
+// replacementBumpPercent above the previous caps and not below the current market fees.
+func (f *FeeStrategy) Bump(ctx context.Context, prev *Fees) (*Fees, error) {
+	tipCap, err := f.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas tip cap: %w", err)
+	}
+
+	header, err := f.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+
+	if header.BaseFee == nil {
+		return nil, errors.New("chain does not support EIP-1559 base fee")
+	}
+
+	bumpedTip := bumpPercent(prev.GasTipCap, replacementBumpPercent)
+	if tipCap.Cmp(bumpedTip) > 0 {
+		bumpedTip = tipCap
+	}
+
+	minFeeCap := bumpPercent(prev.GasFeeCap, replacementBumpPercent)
+	feeCap := new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), bumpedTip)
+	if minFeeCap.Cmp(feeCap) > 0 {
+		feeCap = minFeeCap
+	}
+
+	if f.maxFeePerGas != nil {
+		if minFeeCap.Cmp(f.maxFeePerGas) > 0 {
+			return nil, fmt.Errorf("%w: replacement fee cap %s > max fee per gas %s wei",
+				ErrFeeCeilingExceeded, minFeeCap, f.maxFeePerGas)
+		}
+		if feeCap.Cmp(f.maxFeePerGas) > 0 {
+			feeCap = new(big.Int).Set(f.maxFeePerGas)
+		}
+	}
+
+	if bumpedTip.Cmp(feeCap) > 0 {
+		bumpedTip = new(big.Int).Set(feeCap)
+	}
+
+	return &Fees{
+		GasLimit:  prev.GasLimit,
+		GasTipCap: bumpedTip,
+		GasFeeCap: feeCap,
+	}, nil
+}
+
+// bumpPercent returns v increased by percent, rounded up
+func bumpPercent(v *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(v, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
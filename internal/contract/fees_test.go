@@ -0,0 +1,68 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+type fakeFeeSource struct {
+	gas     uint64
+	tipCap  *big.Int
+	baseFee *big.Int
+}
+
+func (f *fakeFeeSource) EstimateGas(_ context.Context, _ ethereum.CallMsg) (uint64, error) {
+	return f.gas, nil
+}
+
+func (f *fakeFeeSource) SuggestGasTipCap(_ context.Context) (*big.Int, error) {
+	return f.tipCap, nil
+}
+
+func (f *fakeFeeSource) HeaderByNumber(_ context.Context, _ *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: f.baseFee}, nil
+}
+
+func TestFeeStrategy_Estimate(t *testing.T) {
+	source := &fakeFeeSource{gas: 100000, tipCap: big.NewInt(2), baseFee: big.NewInt(10)}
+
+	fees, err := NewFeeStrategy(source, 1.25, nil).Estimate(context.Background(), ethereum.CallMsg{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(125000), fees.GasLimit)
+	assert.Equal(t, big.NewInt(2), fees.GasTipCap)
+	assert.Equal(t, big.NewInt(22), fees.GasFeeCap)
+}
+
+func TestFeeStrategy_EstimateCappedByCeiling(t *testing.T) {
+	source := &fakeFeeSource{gas: 100000, tipCap: big.NewInt(2), baseFee: big.NewInt(10)}
+
+	fees, err := NewFeeStrategy(source, 1, big.NewInt(15)).Estimate(context.Background(), ethereum.CallMsg{})
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(15), fees.GasFeeCap)
+}
+
+func TestFeeStrategy_EstimateCeilingExceeded(t *testing.T) {
+	source := &fakeFeeSource{gas: 100000, tipCap: big.NewInt(2), baseFee: big.NewInt(10)}
+
+	_, err := NewFeeStrategy(source, 1, big.NewInt(11)).Estimate(context.Background(), ethereum.CallMsg{})
+	assert.True(t, errors.Is(err, ErrFeeCeilingExceeded))
+}
+
+func TestFeeStrategy_Bump(t *testing.T) {
+	source := &fakeFeeSource{tipCap: big.NewInt(1), baseFee: big.NewInt(10)}
+	prev := &Fees{GasLimit: 50000, GasTipCap: big.NewInt(10), GasFeeCap: big.NewInt(100)}
+
+	fees, err := NewFeeStrategy(source, 1, nil).Bump(context.Background(), prev)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(50000), fees.GasLimit)
+	assert.Equal(t, big.NewInt(12), fees.GasTipCap)
+	assert.Equal(t, big.NewInt(120), fees.GasFeeCap)
+
+	_, err = NewFeeStrategy(source, 1, big.NewInt(110)).Bump(context.Background(), prev)
+	assert.True(t, errors.Is(err, ErrFeeCeilingExceeded))
+}
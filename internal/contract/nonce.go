@@ -0,0 +1,168 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"nft_service/internal/domain"
+	"strings"
+	"sync"
+)
+
+// nonceSource is the part of the ethereum client the nonce manager relies on
+type nonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out strictly increasing nonces per signer address on one chain.
+// After a restart it continues after the stored transactions that are not mined yet, so it never reuses
+// a nonce the chain has not seen yet, while a nonce that was handed out but never stored is given out again.
+type NonceManager struct {
+	client  nonceSource
+	repo    domain.NonceRepository
+	chainID int64
+	signers map[common.Address]*signerNonce
+	mu      sync.Mutex
+}
+
+type signerNonce struct {
+	next   uint64
+	loaded bool // next nonce is known
+	stale  bool // next nonce must be re-read from the chain, ignoring the stored transactions
+	mu     sync.Mutex
+}
+
+func NewNonceManager(client nonceSource, repo domain.NonceRepository, chainID int64) *NonceManager {
+	return &NonceManager{
+		client:  client,
+		repo:    repo,
+		chainID: chainID,
+		signers: make(map[common.Address]*signerNonce),
+	}
+}
+
+// Next returns the nonce to use for the next transaction of the address
+func (n *NonceManager) Next(ctx context.Context, address common.Address) (uint64, error) {
+	return n.Reserve(ctx, address, 1)
+}
+
+// Reserve hands out count contiguous nonces of the address and returns the first one.
+// Every reserved nonce must be used, otherwise the transactions after the gap are never mined.
+func (n *NonceManager) Reserve(ctx context.Context, address common.Address, count uint64) (uint64, error) {
+	if count == 0 {
+		return 0, errors.New("nonce reservation must not be empty")
+	}
+
+	s := n.signer(address)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded || s.stale {
+		if err := n.sync(ctx, address, s); err != nil {
+			return 0, err
+		}
+	}
+
+	first := s.next
+	s.next += count
+
+	return first, nil
+}
+
+// Resync forces the next call to Next to re-read the pending nonce from the chain.
+// The pending nonce does not cover the nonces handed out to concurrent senders that are not broadcast yet,
+// so one of them can be handed out again. The node rejects the later of the two transactions with a nonce error
+// and sendTransaction retries it with a resynced nonce, up to maxNonceRetries times.
+func (n *NonceManager) Resync(address common.Address) {
+	s := n.signer(address)
+
+	s.mu.Lock()
+	s.stale = true
+	s.mu.Unlock()
+}
+
+// sync loads the next nonce from the chain. On the first load the stored transactions that are not mined yet
+// are taken into account too, because the node may not know about transactions sent before a restart.
+// A nonce handed out without a stored transaction yet is seen by neither, see Resync.
+func (n *NonceManager) sync(ctx context.Context, address common.Address, s *signerNonce) error {
+	pending, err := n.client.PendingNonceAt(ctx, address)
+	if err != nil {
+		return fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+
+	next := pending
+	if !s.loaded {
+		outstanding, found, err := n.repo.GetLastOutstandingNonce(n.chainID, address.Hex())
+		if err != nil {
+			return err
+		}
+		if found && outstanding+1 > next {
+			next = outstanding + 1
+		}
+	}
+
+	s.next = next
+	s.loaded = true
+	s.stale = false
+
+	return nil
+}
+
+func (n *NonceManager) signer(address common.Address) *signerNonce {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	s, ok := n.signers[address]
+	if !ok {
+		s = &signerNonce{}
+		n.signers[address] = s
+	}
+	return s
+}
+
+// isNonceError reports whether the node rejected a transaction because its nonce is already taken
+func isNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "already known") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
+
+// permanentSendErrors are the rejections of the node that sending the same transaction again can not fix.
+// Insufficient funds is not one of them, a no-op filling the nonce could not be paid either, the transaction
+// waits for the broadcaster until the wallet is topped up.
+var permanentSendErrors = []string{
+	"intrinsic gas too low",
+	"exceeds block gas limit",
+	"gas limit reached",
+	"invalid sender",
+	"oversized data",
+	"exceeds the configured cap",
+	"max fee per gas less than block base fee",
+	"max priority fee per gas higher than max fee per gas",
+}
+
+// IsPermanentSendError reports whether the node rejected a transaction for a reason that does not go away,
+// such a transaction is never mined and its nonce has to be filled by another transaction
+func IsPermanentSendError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, permanent := range permanentSendErrors {
+		if strings.Contains(msg, permanent) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInsufficientFundsError reports whether the node rejected a transaction because the wallet can not pay for it
+func IsInsufficientFundsError(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "insufficient funds")
+}
@@ -0,0 +1,154 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+type fakeNonceSource struct {
+	pending uint64
+}
+
+func (f *fakeNonceSource) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	return f.pending, nil
+}
+
+// memoryNonceRepo holds the highest nonce of the outstanding transactions per chain and address
+type memoryNonceRepo struct {
+	nonces map[string]uint64
+}
+
+func (r *memoryNonceRepo) GetLastOutstandingNonce(chainID int64, address string) (uint64, bool, error) {
+	nonce, ok := r.nonces[fmt.Sprintf("%d:%s", chainID, address)]
+	return nonce, ok, nil
+}
+
+var testSigner = common.HexToAddress("0xC92f65c05ccdeF650fe1fdeC0221E5f993ea8956")
+
+const testChainID = 11155111
+
+func TestNonceManager_ConcurrentNext(t *testing.T) {
+	repo := &memoryNonceRepo{nonces: map[string]uint64{}}
+	manager := NewNonceManager(&fakeNonceSource{pending: 7}, repo, testChainID)
+
+	const calls = 50
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[uint64]bool)
+	)
+
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := manager.Next(context.Background(), testSigner)
+			assert.NoError(t, err)
+			mu.Lock()
+			seen[nonce] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, calls)
+	for nonce := uint64(7); nonce < 7+calls; nonce++ {
+		assert.True(t, seen[nonce], "nonce %d was not handed out", nonce)
+	}
+}
+
+func TestNonceManager_UsesOutstandingNonceAfterRestart(t *testing.T) {
+	repo := &memoryNonceRepo{nonces: map[string]uint64{fmt.Sprintf("%d:%s", testChainID, testSigner.Hex()): 10}}
+	manager := NewNonceManager(&fakeNonceSource{pending: 8}, repo, testChainID)
+
+	nonce, err := manager.Next(context.Background(), testSigner)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), nonce)
+}
+
+func TestNonceManager_UsesPendingNonceWithoutOutstandingTransactions(t *testing.T) {
+	// a nonce handed out before the restart but never stored must be handed out again, otherwise it is a gap
+	manager := NewNonceManager(&fakeNonceSource{pending: 8}, &memoryNonceRepo{nonces: map[string]uint64{}}, testChainID)
+
+	nonce, err := manager.Next(context.Background(), testSigner)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8), nonce)
+}
+
+func TestNonceManager_Resync(t *testing.T) {
+	source := &fakeNonceSource{pending: 3}
+	repo := &memoryNonceRepo{nonces: map[string]uint64{}}
+	manager := NewNonceManager(source, repo, testChainID)
+
+	nonce, err := manager.Next(context.Background(), testSigner)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), nonce)
+
+	source.pending = 9
+	manager.Resync(testSigner)
+
+	nonce, err = manager.Next(context.Background(), testSigner)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), nonce)
+
+	nonce, err = manager.Next(context.Background(), testSigner)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), nonce)
+}
+
+func TestIsNonceError(t *testing.T) {
+	assert.True(t, isNonceError(errors.New("nonce too low: next nonce 5, tx nonce 4")))
+	assert.True(t, isNonceError(errors.New("already known")))
+	assert.False(t, isNonceError(errors.New("insufficient funds for gas * price + value")))
+	assert.False(t, isNonceError(nil))
+}
+
+func TestIsPermanentSendError(t *testing.T) {
+	assert.True(t, IsPermanentSendError(errors.New("intrinsic gas too low: have 21000, want 53000")))
+	assert.True(t, IsPermanentSendError(errors.New("exceeds block gas limit")))
+	assert.False(t, IsPermanentSendError(errors.New("insufficient funds for gas * price + value")))
+	assert.False(t, IsPermanentSendError(errors.New("nonce too low")))
+	assert.False(t, IsPermanentSendError(errors.New("connection refused")))
+	assert.False(t, IsPermanentSendError(nil))
+}
+
+func TestIsInsufficientFundsError(t *testing.T) {
+	assert.True(t, IsInsufficientFundsError(errors.New("insufficient funds for gas * price + value: balance 0")))
+	assert.False(t, IsInsufficientFundsError(errors.New("intrinsic gas too low")))
+	assert.False(t, IsInsufficientFundsError(nil))
+}
+
+func TestNonceManager_Reserve(t *testing.T) {
+	repo := &memoryNonceRepo{nonces: map[string]uint64{}}
+	manager := NewNonceManager(&fakeNonceSource{pending: 3}, repo, testChainID)
+
+	first, err := manager.Reserve(context.Background(), testSigner, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), first)
+
+	next, err := manager.Next(context.Background(), testSigner)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8), next)
+
+	_, err = manager.Reserve(context.Background(), testSigner, 0)
+	assert.Error(t, err)
+}
+
+func TestNonceManager_PerChain(t *testing.T) {
+	repo := &memoryNonceRepo{nonces: map[string]uint64{}}
+	sepolia := NewNonceManager(&fakeNonceSource{pending: 5}, repo, testChainID)
+	amoy := NewNonceManager(&fakeNonceSource{pending: 0}, repo, 80002)
+
+	nonce, err := sepolia.Next(context.Background(), testSigner)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), nonce)
+
+	nonce, err = amoy.Next(context.Background(), testSigner)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), nonce, "the nonce of another chain must not be reused")
+}
@@ -0,0 +1,161 @@
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"nft_service/infrastructure/blockchain"
+	"nft_service/infrastructure/config"
+	"nft_service/infrastructure/utils"
+	"nft_service/internal/domain"
+	"sync"
+)
+
+// ErrUnknownCollection is returned for a collection without a registered contract
+var ErrUnknownCollection = errors.New("unknown collection")
+
+// ErrUnknownChain is returned for a chain the service is not configured for
+var ErrUnknownChain = errors.New("unknown chain")
+
+// Registry holds the contract of every collection. The contracts of a chain share its client, nonce manager
+// and fee strategy, since all of them send transactions from the service wallet.
+type Registry struct {
+	cfg       *config.Config
+	chains    map[int64]*chain
+	txRepo    domain.ChainTransactionRepository
+	signer    Signer
+	contracts map[int]*NFTContract
+	defaultID int
+	mu        sync.RWMutex
+}
+
+type chain struct {
+	client *blockchain.Client
+	nonces *NonceManager
+	fees   *FeeStrategy
+}
+
+// NewRegistry prepares the configured chains, clients holds the client of every chain of cfg.Chains
+func NewRegistry(clients map[int64]*blockchain.Client, cfg *config.Config, nonceRepo domain.NonceRepository,
+	txRepo domain.ChainTransactionRepository, signer Signer,
+) *Registry {
+	chains := make(map[int64]*chain, len(cfg.Chains))
+	for _, chainCfg := range cfg.Chains {
+		client := clients[chainCfg.ChainID]
+		chains[chainCfg.ChainID] = &chain{
+			client: client,
+			nonces: NewNonceManager(client, nonceRepo, chainCfg.ChainID),
+			fees:   NewFeeStrategy(client, chainCfg.GasLimitMultiplier, chainCfg.MaxFeePerGas),
+		}
+	}
+
+	return &Registry{
+		cfg:       cfg,
+		chains:    chains,
+		txRepo:    txRepo,
+		signer:    signer,
+		contracts: make(map[int]*NFTContract),
+	}
+}
+
+// Build binds the contract of the collection without registering it. The collection has to be on a configured
+// chain and its ABI reference, when set, has to match the generated bindings.
+func (r *Registry) Build(collection *domain.Collection) (*NFTContract, error) {
+	chain, ok := r.chains[collection.ChainID]
+	if !ok {
+		return nil, fmt.Errorf("%w: collection is on chain %d, which is not configured", ErrUnknownChain,
+			collection.ChainID)
+	}
+
+	if collection.ABIRef != "" {
+		contractABI, err := utils.LoadABIFromFile(collection.ABIRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load contract ABI: %w", err)
+		}
+		if err := CheckABI(contractABI); err != nil {
+			return nil, fmt.Errorf("contract ABI does not match the generated bindings: %w", err)
+		}
+	}
+
+	return NewNFTContract(chain.client, r.cfg, collection.ChainID, common.HexToAddress(collection.Address),
+		chain.nonces, chain.fees, r.txRepo, r.signer)
+}
+
+// Register makes the contract available for the collection
+func (r *Registry) Register(collectionID int, contract *NFTContract) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.contracts[collectionID] = contract
+}
+
+// SetDefault selects the collection served by the routes without a collection id
+func (r *Registry) SetDefault(collectionID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.defaultID = collectionID
+}
+
+func (r *Registry) DefaultID() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.defaultID
+}
+
+// Default returns the contract of the default collection
+func (r *Registry) Default() *NFTContract {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.contracts[r.defaultID]
+}
+
+func (r *Registry) Get(collectionID int) (NFTService, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	contract, ok := r.contracts[collectionID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCollection, collectionID)
+	}
+
+	return contract, nil
+}
+
+// SpeedUp replaces a stuck transaction of any collection. Replacements only reuse the recipient and the data
+// of the stuck transaction, so any contract of its chain sends them.
+func (r *Registry) SpeedUp(stuck *domain.ChainTransaction) (*domain.ChainTransaction, error) {
+	nft, err := r.onChain(stuck.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return nft.SpeedUp(stuck)
+}
+
+// Cancel cancels a stuck transaction of any collection with a contract of its chain
+func (r *Registry) Cancel(stuck *domain.ChainTransaction) (*domain.ChainTransaction, error) {
+	nft, err := r.onChain(stuck.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return nft.Cancel(stuck)
+}
+
+// onChain returns a registered contract of the chain, preferring the default one
+func (r *Registry) onChain(chainID int64) (*NFTContract, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if nft, ok := r.contracts[r.defaultID]; ok && nft.chainID == chainID {
+		return nft, nil
+	}
+	for _, nft := range r.contracts {
+		if nft.chainID == chainID {
+			return nft, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no collection is registered on chain %d", ErrUnknownChain, chainID)
+}
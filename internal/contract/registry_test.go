@@ -0,0 +1,49 @@
+package contract
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nft_service/infrastructure/blockchain"
+	"nft_service/infrastructure/config"
+	"nft_service/internal/domain"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	cfg := &config.Config{Chains: []config.ChainConfig{{ChainID: 11155111}, {ChainID: 80002}}}
+	registry := NewRegistry(map[int64]*blockchain.Client{}, cfg, nil, nil, nil)
+
+	_, err := registry.Build(&domain.Collection{Address: "0x399c1448e0F34aB3722e3aFDd21301Ca6cFF4c4a", ChainID: 1})
+	assert.ErrorIs(t, err, ErrUnknownChain)
+
+	_, err = registry.Build(&domain.Collection{
+		Address: "0x399c1448e0F34aB3722e3aFDd21301Ca6cFF4c4a",
+		ChainID: 11155111,
+		ABIRef:  "missing_abi.json",
+	})
+	assert.ErrorContains(t, err, "failed to load contract ABI")
+
+	nft, err := registry.Build(&domain.Collection{Address: "0x399c1448e0F34aB3722e3aFDd21301Ca6cFF4c4a", ChainID: 11155111})
+	require.NoError(t, err)
+	assert.Same(t, registry.chains[11155111].nonces, nft.nonces, "collections must share the wallet nonces")
+	assert.Equal(t, int64(11155111), nft.ChainID())
+
+	other, err := registry.Build(&domain.Collection{Address: "0x399c1448e0F34aB3722e3aFDd21301Ca6cFF4c4a", ChainID: 80002})
+	require.NoError(t, err)
+	assert.NotSame(t, nft.nonces, other.nonces, "every chain must have its own nonces")
+
+	_, err = registry.Get(1)
+	assert.ErrorIs(t, err, ErrUnknownCollection)
+
+	registry.Register(1, nft)
+	registry.SetDefault(1)
+
+	got, err := registry.Get(1)
+	require.NoError(t, err)
+	assert.Same(t, nft, got)
+	assert.Same(t, nft, registry.Default())
+	assert.Equal(t, 1, registry.DefaultID())
+
+	_, err = registry.SpeedUp(&domain.ChainTransaction{ChainID: 80002})
+	assert.ErrorIs(t, err, ErrUnknownChain)
+}
@@ -0,0 +1,107 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"log/slog"
+	"nft_service/internal/domain"
+	"time"
+)
+
+type TxReplacer interface {
+	SpeedUp(stuck *domain.ChainTransaction) (*domain.ChainTransaction, error)
+	Cancel(stuck *domain.ChainTransaction) (*domain.ChainTransaction, error)
+}
+
+// SpeedUp rebroadcasts a stuck transaction with the same nonce and bumped fees
+func (m *NFTContract) SpeedUp(stuck *domain.ChainTransaction) (*domain.ChainTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	fees, err := m.fees.Bump(ctx, feesOf(stuck))
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := &domain.ChainTransaction{
+		Kind:     stuck.Kind,
+		IsCancel: stuck.IsCancel,
+		Intent:   stuck.Intent,
+		SpeedUps: stuck.SpeedUps + 1,
+		Replaces: stuck.TxHash,
+	}
+
+	return m.replace(ctx, stuck, replacement, common.HexToAddress(stuck.ToAddress), stuck.Data, fees)
+}
+
+// Cancel replaces a stuck transaction with a zero-value self-transfer using the same nonce
+func (m *NFTContract) Cancel(stuck *domain.ChainTransaction) (*domain.ChainTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	fees, err := m.fees.Bump(ctx, feesOf(stuck))
+	if err != nil {
+		return nil, err
+	}
+	fees.GasLimit = params.TxGas
+
+	replacement := &domain.ChainTransaction{
+		Kind:     stuck.Kind,
+		IsCancel: true,
+		Intent:   stuck.Intent,
+		SpeedUps: stuck.SpeedUps,
+		Replaces: stuck.TxHash,
+	}
+
+	return m.replace(ctx, stuck, replacement, m.signer.Address(), nil, fees)
+}
+
+func (m *NFTContract) replace(ctx context.Context, stuck, replacement *domain.ChainTransaction,
+	to common.Address, txData []byte, fees *Fees,
+) (*domain.ChainTransaction, error) {
+	l := slog.Default()
+
+	signedTx, err := m.signTransaction(ctx, stuck.Nonce, to, txData, fees)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := m.storeTransaction(replacement, signedTx); err != nil {
+		return nil, err
+	}
+
+	if err := m.client.SendTransaction(ctx, signedTx); err != nil {
+		// the stuck transaction stays the one to track
+		if discardErr := m.txRepo.Discard(replacement.TxHash); discardErr != nil {
+			l.Error("failed to discard replacement transaction", slog.String("tx_hash", replacement.TxHash),
+				slog.Any("error", discardErr))
+		}
+		return nil, fmt.Errorf("failed to send replacement transaction: %w", err)
+	}
+
+	m.markBroadcast(replacement)
+
+	if err := m.txRepo.MarkReplaced(stuck.TxHash, replacement.TxHash); err != nil {
+		return nil, err
+	}
+
+	l.Info("stuck transaction replaced",
+		slog.String("tx_hash", stuck.TxHash),
+		slog.String("replaced_by", replacement.TxHash),
+		slog.Bool("cancel", replacement.IsCancel),
+		slog.Uint64("nonce", stuck.Nonce),
+		slog.String("gas_fee_cap", fees.GasFeeCap.String()),
+	)
+
+	return replacement, nil
+}
+
+func feesOf(tx *domain.ChainTransaction) *Fees {
+	return &Fees{
+		GasLimit:  tx.GasLimit,
+		GasTipCap: tx.GasTipCap,
+		GasFeeCap: tx.GasFeeCap,
+	}
+}
@@ -0,0 +1,115 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"log/slog"
+	"nft_service/internal/domain"
+	"strings"
+	"time"
+)
+
+const DefaultAdminRole = "DEFAULT_ADMIN_ROLE"
+
+// ErrMissingRole is returned when an account does not hold a role it needs
+var ErrMissingRole = errors.New("missing role")
+
+// RoleID resolves a role to its bytes32 id. A 0x prefixed 32 byte hex string is the id itself, DEFAULT_ADMIN_ROLE
+// is the zero id and any other name is hashed with keccak256 like the role constants of AccessControl.
+func RoleID(role string) (common.Hash, error) {
+	switch {
+	case role == "":
+		return common.Hash{}, errors.New("role must not be empty")
+	case role == DefaultAdminRole:
+		return common.Hash{}, nil
+	case strings.HasPrefix(role, "0x"):
+		id, err := hexutil.Decode(role)
+		if err != nil || len(id) != common.HashLength {
+			return common.Hash{}, errors.New("invalid role id " + role)
+		}
+		return common.BytesToHash(id), nil
+	default:
+		return crypto.Keccak256Hash([]byte(role)), nil
+	}
+}
+
+// CheckRole returns ErrMissingRole when the account does not hold the role on the contract
+func CheckRole(nft NFTService, role, account string) error {
+	id, err := RoleID(role)
+	if err != nil {
+		return err
+	}
+
+	hasRole, err := nft.HasRole(id, account)
+	if err != nil {
+		return fmt.Errorf("failed to check role %s: %w", role, err)
+	}
+
+	if !hasRole {
+		return fmt.Errorf("%w: %s does not have role %s (%s)", ErrMissingRole, account, role, id.Hex())
+	}
+
+	return nil
+}
+
+// HasRole reports whether the account holds the role. Roles change through this service, so the result is not cached.
+func (m *NFTContract) HasRole(role common.Hash, account string) (bool, error) {
+	return view("hasRole", func(opts *bind.CallOpts) (bool, error) {
+		return m.nft.HasRole(opts, role, common.HexToAddress(account))
+	})
+}
+
+// GetRoleAdmin returns the role whose members may grant and revoke the role
+func (m *NFTContract) GetRoleAdmin(role common.Hash) (common.Hash, error) {
+	admin, err := cachedView(m, "getRoleAdmin", []any{role}, func(opts *bind.CallOpts) ([32]byte, error) {
+		return m.nft.GetRoleAdmin(opts, role)
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return admin, nil
+}
+
+// GrantRole sends grantRole(role, account) from the service wallet and returns the transaction hash
+func (m *NFTContract) GrantRole(role common.Hash, account string) (string, error) {
+	return m.sendRoleTransaction("grantRole", role, account, m.nft.GrantRole)
+}
+
+// RevokeRole sends revokeRole(role, account) from the service wallet and returns the transaction hash
+func (m *NFTContract) RevokeRole(role common.Hash, account string) (string, error) {
+	return m.sendRoleTransaction("revokeRole", role, account, m.nft.RevokeRole)
+}
+
+func (m *NFTContract) sendRoleTransaction(method string, role common.Hash, account string,
+	transact func(opts *bind.TransactOpts, role [32]byte, account common.Address) (*types.Transaction, error),
+) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	txData, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return transact(opts, role, common.HexToAddress(account))
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to pack %s data: %w", method, err)
+	}
+
+	signedTx, err := m.sendTransaction(ctx, domain.ChainTxKindRole, txData, nil)
+	if err != nil {
+		return "", err
+	}
+
+	slog.Default().Info("role transaction sent",
+		slog.String("method", method),
+		slog.String("role", role.Hex()),
+		slog.String("account", account),
+		slog.String("tx_hash", signedTx.Hash().Hex()),
+	)
+
+	return signedTx.Hash().Hex(), nil
+}
@@ -0,0 +1,36 @@
+package contract
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRoleID(t *testing.T) {
+	tests := []struct {
+		role      string
+		expected  common.Hash
+		expectErr bool
+	}{
+		{role: "DEFAULT_ADMIN_ROLE", expected: common.Hash{}},
+		{role: "MINTER_ROLE", expected: common.HexToHash("0x9f2df0fed2c77648de5860a4cc508cd0818c85b8b8a1ab4ceeef8d981c8956a6")},
+		{
+			role:     "0x9f2df0fed2c77648de5860a4cc508cd0818c85b8b8a1ab4ceeef8d981c8956a6",
+			expected: common.HexToHash("0x9f2df0fed2c77648de5860a4cc508cd0818c85b8b8a1ab4ceeef8d981c8956a6"),
+		},
+		{role: "0x1234", expectErr: true},
+		{role: "", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			id, err := RoleID(tt.role)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, id)
+		})
+	}
+}
@@ -3,47 +3,90 @@ package contract
 import (
 	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/common"
 	"math/big"
+	"nft_service/infrastructure/blockchain"
 	"nft_service/infrastructure/config"
+	"nft_service/internal/contract/bindings"
 	"nft_service/internal/domain"
-	"strings"
 	"sync"
 )
 
 type NFTService interface {
 	Mint(token *domain.Token) (*domain.Token, error)
+	MintBatch(tokens []*domain.Token) []error
 	TotalSupply() (*big.Int, error)
 	ExactTotalSupply() (*big.Int, error)
 	TransferToken(transfer *domain.Transfer) (*domain.Transfer, error)
+	Approve(approval *domain.Approval) (*domain.Approval, error)
+	SetApprovalForAll(approval *domain.Approval) (*domain.Approval, error)
+	GetApproved(tokenID *big.Int) (string, error)
+	IsApprovedForAll(owner, operator string) (bool, error)
+	HasRole(role common.Hash, account string) (bool, error)
+	GetRoleAdmin(role common.Hash) (common.Hash, error)
+	GrantRole(role common.Hash, account string) (string, error)
+	RevokeRole(role common.Hash, account string) (string, error)
+	OwnerOf(tokenID *big.Int) (string, error)
+	TokenURI(tokenID *big.Int) (string, error)
+	BalanceOf(owner string) (*big.Int, error)
+	TokenOfOwnerByIndex(owner string, index *big.Int) (*big.Int, error)
+	TokenByIndex(index *big.Int) (*big.Int, error)
+	HashToID(uniqueHash string) (*big.Int, error)
+	Name() (string, error)
+	Symbol() (string, error)
+	ChainID() int64
 }
 
 type NFTContract struct {
-	client       *ethclient.Client
+	client       *blockchain.Client
 	cfg          *config.Config
-	contractABI  string
-	parsedABI    *abi.ABI
+	chainID      int64
+	address      common.Address
+	nft          *bindings.NFT
+	parsedABI    *abi.ABI // ABI of the bindings, used to decode custom revert errors
 	cache        *big.Int
 	cacheUpdated int64
+	nonces       *NonceManager
+	fees         *FeeStrategy
+	txRepo       domain.ChainTransactionRepository
+	signer       Signer
+	views        *viewCache
 	mu           sync.RWMutex
 }
 
-func NewNFTContract(url string, cfg *config.Config, contractABI string) (*NFTContract, error) {
-	client, err := ethclient.Dial(url)
+// NewNFTContract binds the contract at the address on the chain of the client. Contracts sending
+// from the same wallet on the same chain must share the nonce manager.
+func NewNFTContract(client *blockchain.Client, cfg *config.Config, chainID int64, address common.Address,
+	nonces *NonceManager, fees *FeeStrategy, txRepo domain.ChainTransactionRepository, signer Signer,
+) (*NFTContract, error) {
+	parsedAbi, err := bindings.NFTMetaData.GetAbi()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse contract ABI: %w", err)
 	}
 
-	parsedAbi, err := abi.JSON(strings.NewReader(contractABI))
+	nft, err := bindings.NewNFT(address, client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse contract ABI: %w", err)
+		return nil, fmt.Errorf("failed to bind contract: %w", err)
 	}
 
 	contract := &NFTContract{
 		client:    client,
 		cfg:       cfg,
-		parsedABI: &parsedAbi,
+		chainID:   chainID,
+		address:   address,
+		nft:       nft,
+		parsedABI: parsedAbi,
+		nonces:    nonces,
+		fees:      fees,
+		txRepo:    txRepo,
+		signer:    signer,
+		views:     newViewCache(cfg.ViewCacheTTL),
 	}
 
 	return contract, nil
 }
+
+// ChainID returns the chain the contract is deployed on
+func (m *NFTContract) ChainID() int64 {
+	return m.chainID
+}
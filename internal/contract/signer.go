@@ -0,0 +1,216 @@
+package contract
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"log/slog"
+	"math/big"
+	"nft_service/infrastructure/config"
+	"os"
+	"strings"
+)
+
+const (
+	SignerTypeRaw      = "raw"
+	SignerTypeKeystore = "keystore"
+	SignerTypeRemote   = "remote"
+)
+
+// Signer signs transactions of the service wallet
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// NewSigner creates the signer selected by SIGNER_TYPE and checks it signs for USER_ADDRESS
+func NewSigner(cfg *config.Config) (Signer, error) {
+	var (
+		signer Signer
+		err    error
+	)
+
+	switch cfg.SignerType {
+	case SignerTypeRaw:
+		slog.Default().Warn("raw private key signer is meant for development only, use keystore or remote signer")
+		signer, err = NewRawKeySigner(cfg.UserPrivateKey)
+	case SignerTypeKeystore:
+		signer, err = NewKeystoreSigner(cfg.KeystorePath, cfg.KeystorePasswordFile)
+	case SignerTypeRemote:
+		signer, err = NewRemoteSigner(cfg.RemoteSignerURL, common.HexToAddress(cfg.UserAddress))
+	default:
+		return nil, fmt.Errorf("unknown signer type %q", cfg.SignerType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if signer.Address() != common.HexToAddress(cfg.UserAddress) {
+		return nil, fmt.Errorf("signer address %s does not match USER_ADDRESS %s", signer.Address().Hex(), cfg.UserAddress)
+	}
+
+	return signer, nil
+}
+
+// PrivateKeySigner signs with a private key held in memory
+type PrivateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewRawKeySigner creates a signer from a hex encoded private key
+func NewRawKeySigner(hexKey string) (*PrivateKeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert private key: %w", err)
+	}
+
+	return newPrivateKeySigner(key), nil
+}
+
+// NewKeystoreSigner creates a signer from a go-ethereum encrypted keystore JSON file,
+// the passphrase is read from passwordFile
+func NewKeystoreSigner(keyFile, passwordFile string) (*PrivateKeySigner, error) {
+	keyJSON, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %w", err)
+	}
+
+	password, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore password file: %w", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, strings.TrimRight(string(password), "\r\n"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+
+	return newPrivateKeySigner(key.PrivateKey), nil
+}
+
+func newPrivateKeySigner(key *ecdsa.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *PrivateKeySigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// RemoteSigner signs through an external signer speaking the eth_signTransaction JSON-RPC API (Clef, Web3Signer)
+type RemoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+type signTransactionArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+func NewRemoteSigner(url string, address common.Address) (*RemoteSigner, error) {
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer: %w", err)
+	}
+
+	return &RemoteSigner{
+		client:  client,
+		address: address,
+	}, nil
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := signTransactionArgs{
+		From:                 s.address,
+		To:                   tx.To(),
+		Gas:                  hexutil.Uint64(tx.Gas()),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasFeeCap()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasTipCap()),
+		Value:                (*hexutil.Big)(tx.Value()),
+		Nonce:                hexutil.Uint64(tx.Nonce()),
+		Data:                 tx.Data(),
+		ChainID:              (*hexutil.Big)(chainID),
+	}
+
+	var result json.RawMessage
+	if err := s.client.CallContext(ctx, &result, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("remote signer failed to sign transaction: %w", err)
+	}
+
+	raw, err := decodeSignResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	sender, err := types.Sender(signer, signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover signed transaction sender: %w", err)
+	}
+
+	if sender != s.address {
+		return nil, fmt.Errorf("remote signer signed with %s instead of %s", sender.Hex(), s.address.Hex())
+	}
+
+	// the signing hash covers the chain id, nonce, fee caps, gas, recipient, value and data
+	if signer.Hash(signedTx) != signer.Hash(tx) {
+		return nil, errors.New("remote signer returned a different transaction than requested")
+	}
+
+	return signedTx, nil
+}
+
+// decodeSignResult extracts the raw transaction from an eth_signTransaction result.
+// Clef returns {"raw": "0x..", "tx": {..}}, Web3Signer returns the raw transaction as a string.
+func decodeSignResult(result json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+
+	if err := json.Unmarshal(result, &raw); err == nil {
+		return raw, nil
+	}
+
+	var object struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(result, &object); err != nil || len(object.Raw) == 0 {
+		return nil, errors.New("unexpected eth_signTransaction result")
+	}
+
+	return object.Raw, nil
+}
@@ -0,0 +1,157 @@
+package contract
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// standInSigner is a local stand-in for Clef answering eth_signTransaction
+type standInSigner struct {
+	key    *ecdsa.PrivateKey
+	tamper func(tx *types.DynamicFeeTx) // changes the transaction before signing
+}
+
+func (s *standInSigner) SignTransaction(args signTransactionArgs) (map[string]hexutil.Bytes, error) {
+	txData := &types.DynamicFeeTx{
+		ChainID:   args.ChainID.ToInt(),
+		Nonce:     uint64(args.Nonce),
+		GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: args.MaxFeePerGas.ToInt(),
+		Gas:       uint64(args.Gas),
+		To:        args.To,
+		Value:     args.Value.ToInt(),
+		Data:      args.Data,
+	}
+	if s.tamper != nil {
+		s.tamper(txData)
+	}
+
+	signedTx, err := types.SignTx(types.NewTx(txData), types.LatestSignerForChainID(args.ChainID.ToInt()), s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]hexutil.Bytes{"raw": raw}, nil
+}
+
+func testTransaction() *types.Transaction {
+	to := common.HexToAddress("0x399c1448e0F34aB3722e3aFDd21301Ca6cFF4c4a")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(11155111),
+		Nonce:     4,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      []byte{0x01, 0x02},
+	})
+}
+
+func assertSignedBy(t *testing.T, signedTx *types.Transaction, address common.Address) {
+	sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(11155111)), signedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, address, sender)
+}
+
+func TestRemoteSigner_SignTx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", &standInSigner{key: key}))
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	signer, err := NewRemoteSigner(httpServer.URL, address)
+	assert.NoError(t, err)
+
+	signedTx, err := signer.SignTx(context.Background(), testTransaction(), big.NewInt(11155111))
+	assert.NoError(t, err)
+	assertSignedBy(t, signedTx, address)
+	assert.Equal(t, uint64(4), signedTx.Nonce())
+
+	otherKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	signer, err = NewRemoteSigner(httpServer.URL, crypto.PubkeyToAddress(otherKey.PublicKey))
+	assert.NoError(t, err)
+
+	_, err = signer.SignTx(context.Background(), testTransaction(), big.NewInt(11155111))
+	assert.ErrorContains(t, err, "remote signer signed with")
+}
+
+func TestRemoteSigner_SignTx_DifferentTransaction(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(tx *types.DynamicFeeTx)
+	}{
+		{name: "contract creation", tamper: func(tx *types.DynamicFeeTx) { tx.To = nil }},
+		{name: "fee cap", tamper: func(tx *types.DynamicFeeTx) { tx.GasFeeCap = big.NewInt(1_000_000) }},
+		{name: "tip cap", tamper: func(tx *types.DynamicFeeTx) { tx.GasTipCap = big.NewInt(2) }},
+		{name: "nonce", tamper: func(tx *types.DynamicFeeTx) { tx.Nonce++ }},
+		{name: "value", tamper: func(tx *types.DynamicFeeTx) { tx.Value = big.NewInt(1) }},
+		{name: "data", tamper: func(tx *types.DynamicFeeTx) { tx.Data = []byte{0x03} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := crypto.GenerateKey()
+			assert.NoError(t, err)
+
+			server := rpc.NewServer()
+			assert.NoError(t, server.RegisterName("eth", &standInSigner{key: key, tamper: tt.tamper}))
+			httpServer := httptest.NewServer(server)
+			defer httpServer.Close()
+
+			signer, err := NewRemoteSigner(httpServer.URL, crypto.PubkeyToAddress(key.PublicKey))
+			assert.NoError(t, err)
+
+			_, err = signer.SignTx(context.Background(), testTransaction(), big.NewInt(11155111))
+			assert.ErrorContains(t, err, "different transaction than requested")
+		})
+	}
+}
+
+func TestKeystoreSigner_SignTx(t *testing.T) {
+	dir := t.TempDir()
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, "secret")
+	assert.NoError(t, err)
+
+	passwordFile := filepath.Join(dir, "password")
+	assert.NoError(t, os.WriteFile(passwordFile, []byte("secret\n"), 0600))
+
+	signer, err := NewKeystoreSigner(account.URL.Path, passwordFile)
+	assert.NoError(t, err)
+	assert.Equal(t, account.Address, signer.Address())
+
+	signedTx, err := signer.SignTx(context.Background(), testTransaction(), big.NewInt(11155111))
+	assert.NoError(t, err)
+	assertSignedBy(t, signedTx, account.Address)
+
+	assert.NoError(t, os.WriteFile(passwordFile, []byte("wrong"), 0600))
+	_, err = NewKeystoreSigner(account.URL.Path, passwordFile)
+	assert.ErrorContains(t, err, "failed to decrypt keystore")
+}
@@ -0,0 +1,143 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"strings"
+)
+
+const (
+	RevertKindError   = "error"
+	RevertKindPanic   = "panic"
+	RevertKindCustom  = "custom"
+	RevertKindUnknown = "unknown"
+)
+
+var (
+	// selector of Error(string), used by require and revert with a message
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// selector of Panic(uint256), used by failed asserts, overflows and out of bounds access
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicReasons are the panic codes of the Solidity compiler
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assert failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to uninitialized function",
+}
+
+// RevertError is returned when the simulation of a transaction reverts, so it is not broadcast
+type RevertError struct {
+	Kind   string         `json:"kind"`
+	Reason string         `json:"reason"`
+	Name   string         `json:"name,omitempty"`
+	Args   map[string]any `json:"args,omitempty"`
+	Data   string         `json:"data,omitempty"`
+}
+
+func (e *RevertError) Error() string {
+	return "execution reverted: " + e.Reason
+}
+
+// simulate runs the call data as the service wallet against the pending block and returns a *RevertError
+// if the transaction would revert
+func (m *NFTContract) simulate(ctx context.Context, txData []byte) error {
+	toAddress := m.address
+
+	_, err := m.client.PendingCallContract(ctx, ethereum.CallMsg{
+		From:  m.signer.Address(),
+		To:    &toAddress,
+		Value: big.NewInt(0),
+		Data:  txData,
+	})
+	if err == nil {
+		return nil
+	}
+
+	if revertErr := revertError(m.parsedABI, err); revertErr != nil {
+		return revertErr
+	}
+	return fmt.Errorf("failed to simulate transaction: %w", err)
+}
+
+// revertError extracts the revert data from an eth_call error, nil if the call did not revert
+func revertError(parsedABI *abi.ABI, err error) *RevertError {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if raw, decodeErr := hexutil.Decode(data); decodeErr == nil {
+				return decodeRevert(parsedABI, raw)
+			}
+		}
+	}
+
+	if strings.Contains(err.Error(), "execution reverted") {
+		return &RevertError{Kind: RevertKindUnknown, Reason: strings.TrimPrefix(err.Error(), "execution reverted: ")}
+	}
+	return nil
+}
+
+// decodeRevert decodes Error(string), Panic(uint256) and the custom errors of the contract ABI
+func decodeRevert(parsedABI *abi.ABI, data []byte) *RevertError {
+	revert := &RevertError{Kind: RevertKindUnknown, Reason: "execution reverted"}
+	if len(data) > 0 {
+		revert.Data = hexutil.Encode(data)
+	}
+	if len(data) < 4 {
+		return revert
+	}
+
+	selector, payload := data[:4], data[4:]
+
+	switch {
+	case string(selector) == string(errorSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			revert.Kind, revert.Reason = RevertKindError, reason
+		}
+	case string(selector) == string(panicSelector):
+		if len(payload) == 32 {
+			code := new(big.Int).SetBytes(payload)
+			reason, ok := panicReasons[code.Uint64()]
+			if !ok || !code.IsUint64() {
+				reason = "unknown panic"
+			}
+			revert.Kind, revert.Reason = RevertKindPanic, fmt.Sprintf("%s (0x%x)", reason, code)
+		}
+	default:
+		for name, abiErr := range parsedABI.Errors {
+			if string(abiErr.ID[:4]) != string(selector) {
+				continue
+			}
+			unpacked, err := abiErr.Inputs.Unpack(payload)
+			if err != nil {
+				break
+			}
+			revert.Kind, revert.Name, revert.Reason = RevertKindCustom, name, abiErr.Sig
+			revert.Args = make(map[string]any, len(unpacked))
+			for i, input := range abiErr.Inputs {
+				key := input.Name
+				if key == "" {
+					key = fmt.Sprintf("arg%d", i)
+				}
+				revert.Args[key] = fmt.Sprint(unpacked[i])
+			}
+			break
+		}
+	}
+
+	return revert
+}
@@ -0,0 +1,78 @@
+package contract
+
+import (
+	"errors"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const errorsABI = `[{"type":"error","name":"ERC721IncorrectOwner","inputs":[{"name":"sender","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"owner","type":"address"}]}]`
+
+type dataError struct {
+	data string
+}
+
+func (e dataError) Error() string          { return "execution reverted" }
+func (e dataError) ErrorCode() int         { return 3 }
+func (e dataError) ErrorData() interface{} { return e.data }
+
+func packRevert(t *testing.T, selector []byte, typ string, value any) []byte {
+	argType, err := abi.NewType(typ, "", nil)
+	require.NoError(t, err)
+	payload, err := abi.Arguments{{Type: argType}}.Pack(value)
+	require.NoError(t, err)
+	return append(append([]byte{}, selector...), payload...)
+}
+
+func TestDecodeRevert(t *testing.T) {
+	parsedABI, err := abi.JSON(strings.NewReader(errorsABI))
+	require.NoError(t, err)
+
+	t.Run("error string", func(t *testing.T) {
+		revert := decodeRevert(&parsedABI, packRevert(t, errorSelector, "string", "ERC721: caller is not token owner"))
+		assert.Equal(t, RevertKindError, revert.Kind)
+		assert.Equal(t, "ERC721: caller is not token owner", revert.Reason)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		revert := decodeRevert(&parsedABI, packRevert(t, panicSelector, "uint256", big.NewInt(0x11)))
+		assert.Equal(t, RevertKindPanic, revert.Kind)
+		assert.Equal(t, "arithmetic overflow or underflow (0x11)", revert.Reason)
+	})
+
+	t.Run("custom error", func(t *testing.T) {
+		customErr := parsedABI.Errors["ERC721IncorrectOwner"]
+		payload, err := customErr.Inputs.Pack(
+			common.HexToAddress("0x01"), big.NewInt(7), common.HexToAddress("0x02"),
+		)
+		require.NoError(t, err)
+
+		revert := decodeRevert(&parsedABI, append(customErr.ID[:4], payload...))
+		assert.Equal(t, RevertKindCustom, revert.Kind)
+		assert.Equal(t, "ERC721IncorrectOwner", revert.Name)
+		assert.Equal(t, "7", revert.Args["tokenId"])
+	})
+
+	t.Run("unknown data", func(t *testing.T) {
+		revert := decodeRevert(&parsedABI, []byte{0xde, 0xad})
+		assert.Equal(t, RevertKindUnknown, revert.Kind)
+		assert.Equal(t, "0xdead", revert.Data)
+	})
+}
+
+func TestRevertError(t *testing.T) {
+	parsedABI, err := abi.JSON(strings.NewReader(errorsABI))
+	require.NoError(t, err)
+
+	data := packRevert(t, errorSelector, "string", "not approved")
+	revert := revertError(&parsedABI, dataError{data: "0x" + common.Bytes2Hex(data)})
+	require.NotNil(t, revert)
+	assert.Equal(t, "not approved", revert.Reason)
+
+	assert.Nil(t, revertError(&parsedABI, errors.New("connection refused")))
+}
@@ -2,9 +2,6 @@ package contract
 
 import (
 	"context"
-	"fmt"
-	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/common"
 	"log/slog"
 	"math/big"
 	"time"
@@ -35,35 +32,7 @@ func (m *NFTContract) ExactTotalSupply() (*big.Int, error) {
 
 // getTotalSupplyFromContract returns total supply from contract
 func (m *NFTContract) getTotalSupplyFromContract() (*big.Int, error) {
-	var (
-		toAddress   = common.HexToAddress(m.cfg.ContractAddress)
-		totalSupply *big.Int
-	)
-
-	callData, err := m.parsedABI.Pack("totalSupply")
-	if err != nil {
-		return nil, fmt.Errorf("failed to pack totalSupply call data: %w", err)
-	}
-
-	msg := ethereum.CallMsg{
-		To:   &toAddress,
-		Data: callData,
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	result, err := m.client.CallContract(ctx, msg, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call totalSupply: %w", err)
-	}
-
-	err = m.parsedABI.UnpackIntoInterface(&totalSupply, "totalSupply", result)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unpack totalSupply result: %w", err)
-	}
-
-	return totalSupply, nil
+	return view("totalSupply", m.nft.TotalSupply)
 }
 
 // updateTotalSupplyCache update cache with total supply
@@ -0,0 +1,159 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"log/slog"
+	"math/big"
+	"nft_service/internal/domain"
+)
+
+// maxNonceRetries is how many times a transaction is re-signed with a fresh nonce after a nonce conflict
+const maxNonceRetries = 3
+
+// sendTransaction signs the call data to the contract with the next nonce of the service wallet, stores it with
+// the rows of the intent and broadcasts it, so the stuck transaction monitor can speed it up or cancel it later.
+// A transaction that would revert is not signed, a *RevertError is returned instead. A transaction the node
+// rejected permanently is discarded and its nonce filled with a no-op, other rejections are left to the broadcaster.
+func (m *NFTContract) sendTransaction(ctx context.Context, kind string, txData []byte, intent *domain.TxIntent) (*types.Transaction, error) {
+	var (
+		l           = slog.Default()
+		fromAddress = m.signer.Address()
+		toAddress   = m.address
+	)
+
+	if err := m.simulate(ctx, txData); err != nil {
+		return nil, err
+	}
+
+	fees, err := m.fees.Estimate(ctx, ethereum.CallMsg{
+		From:  fromAddress,
+		To:    &toAddress,
+		Value: big.NewInt(0),
+		Data:  txData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkFunds(ctx, m.client, fromAddress, maxCost(fees)); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		nonce, err := m.nonces.Next(ctx, fromAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		signedTx, err := m.signTransaction(ctx, nonce, toAddress, txData, fees)
+		if err != nil {
+			return nil, err
+		}
+
+		record, err := m.storeTransaction(&domain.ChainTransaction{Kind: kind, Intent: intent}, signedTx)
+		if err != nil {
+			// nothing was broadcast, the nonce is free again
+			m.nonces.Resync(fromAddress)
+			return nil, err
+		}
+
+		err = m.client.SendTransaction(ctx, signedTx)
+		if err == nil {
+			m.markBroadcast(record)
+			return signedTx, nil
+		}
+
+		if IsPermanentSendError(err) {
+			// the rows were never sent, the nonce may already be followed by other transactions
+			if discardErr := m.txRepo.Discard(record.TxHash); discardErr != nil {
+				return nil, discardErr
+			}
+			m.fillNonce(ctx, nonce, fees)
+			return nil, fmt.Errorf("transaction rejected by the node: %w", err)
+		}
+
+		if !isNonceError(err) {
+			l.Warn("transaction stored but not broadcast, the broadcaster will retry",
+				slog.String("tx_hash", record.TxHash),
+				slog.Any("error", err),
+			)
+			return signedTx, nil
+		}
+
+		// the nonce is taken, the transaction can never be mined
+		if discardErr := m.txRepo.Discard(record.TxHash); discardErr != nil {
+			return nil, discardErr
+		}
+		m.nonces.Resync(fromAddress)
+
+		if attempt >= maxNonceRetries {
+			return nil, fmt.Errorf("failed to send transaction: %w", err)
+		}
+
+		l.Warn("nonce conflict, retrying with resynced nonce",
+			slog.Uint64("nonce", nonce),
+			slog.Int("attempt", attempt+1),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// signTransaction builds a zero-value dynamic fee transaction and signs it with the service wallet
+func (m *NFTContract) signTransaction(ctx context.Context, nonce uint64, to common.Address, txData []byte, fees *Fees) (*types.Transaction, error) {
+	chainID := big.NewInt(m.chainID)
+
+	unsignedTx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: fees.GasTipCap,
+		GasFeeCap: fees.GasFeeCap,
+		Gas:       fees.GasLimit,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      txData,
+	})
+
+	return m.signer.SignTx(ctx, unsignedTx, chainID)
+}
+
+// storeTransaction stores a signed transaction before it is broadcast together with the rows of its intent
+func (m *NFTContract) storeTransaction(record *domain.ChainTransaction, signedTx *types.Transaction) (*domain.ChainTransaction, error) {
+	rawTx, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signed transaction: %w", err)
+	}
+
+	record.ChainID = m.chainID
+	record.TxHash = signedTx.Hash().Hex()
+	record.FromAddress = m.signer.Address().Hex()
+	record.ToAddress = signedTx.To().Hex()
+	record.Nonce = signedTx.Nonce()
+	record.Data = signedTx.Data()
+	record.RawTx = rawTx
+	record.GasLimit = signedTx.Gas()
+	record.GasTipCap = signedTx.GasTipCap()
+	record.GasFeeCap = signedTx.GasFeeCap()
+
+	if err := m.txRepo.CreateSigned(record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// markBroadcast records that the node accepted the transaction. A failed update is only logged,
+// the broadcaster sends the transaction again and the node answers that it already knows it.
+func (m *NFTContract) markBroadcast(record *domain.ChainTransaction) {
+	if err := m.txRepo.MarkBroadcast(record.TxHash); err != nil {
+		slog.Default().Error("failed to mark chain transaction broadcast",
+			slog.String("tx_hash", record.TxHash),
+			slog.Any("error", err),
+		)
+		return
+	}
+	record.Status = domain.ChainTxStatusPending
+}
@@ -3,9 +3,9 @@ package contract
 import (
 	"context"
 	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"log/slog"
 	"math/big"
 	"nft_service/internal/domain"
@@ -25,55 +25,26 @@ func (m *NFTContract) TransferToken(transfer *domain.Transfer) (*domain.Transfer
 		return nil, fmt.Errorf("invalid token id")
 	}
 
-	txData, err := m.parsedABI.Pack("safeTransferFrom", common.HexToAddress(transfer.FromAddress), common.HexToAddress(transfer.ToAddress), tokenId)
-	if err != nil {
-		return nil, fmt.Errorf("failed to pack transfer data: %w", err)
-	}
-
-	nonce, err := m.client.PendingNonceAt(ctx, common.HexToAddress(m.cfg.UserAddress))
-	if err != nil {
-		return nil, fmt.Errorf("failed to get pending nonce: %w", err)
-	}
-
-	gasPrice, err := m.client.SuggestGasPrice(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to suggest gas price: %w", err)
-	}
-
-	toAddress := common.HexToAddress(m.cfg.ContractAddress)
-
-	unsignedTx := types.NewTx(&types.DynamicFeeTx{
-		ChainID:   big.NewInt(m.cfg.ChainID),
-		Nonce:     nonce,
-		GasTipCap: gasPrice,
-		GasFeeCap: gasPrice,
-		Gas:       uint64(300000),
-		To:        &toAddress,
-		Value:     big.NewInt(0),
-		Data:      txData,
+	txData, err := calldata(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return m.nft.SafeTransferFrom(opts, common.HexToAddress(transfer.FromAddress), common.HexToAddress(transfer.ToAddress), tokenId)
 	})
-
-	privateKey, err := crypto.HexToECDSA(m.cfg.UserPrivateKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert private key: %w", err)
+		return nil, fmt.Errorf("failed to pack transfer data: %w", err)
 	}
 
-	signedTx, err := types.SignTx(unsignedTx, types.LatestSignerForChainID(big.NewInt(m.cfg.ChainID)), privateKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
-	}
+	transfer.Status = domain.TransferStatusPending
 
-	err = m.client.SendTransaction(ctx, signedTx)
+	signedTx, err := m.sendTransaction(ctx, domain.ChainTxKindTransfer, txData, &domain.TxIntent{Transfer: transfer})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
+		return nil, err
 	}
 
 	transfer.TxHash = signedTx.Hash().Hex()
-	transfer.Status = "pending"
 
 	latency := time.Now().Sub(startTime).Milliseconds()
 	l.Info("transfer transaction sent",
 		slog.String("tx_hash", signedTx.Hash().Hex()),
+		slog.Uint64("nonce", signedTx.Nonce()),
 		slog.Float64("latency", float64(latency)*0.001),
 	)
 
@@ -0,0 +1,96 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ErrCallReverted is returned when a view call reverts, e.g. ownerOf for a token that does not exist
+var ErrCallReverted = errors.New("contract call reverted")
+
+func (m *NFTContract) OwnerOf(tokenID *big.Int) (string, error) {
+	owner, err := cachedView(m, "ownerOf", []any{tokenID}, func(opts *bind.CallOpts) (common.Address, error) {
+		return m.nft.OwnerOf(opts, tokenID)
+	})
+	if err != nil {
+		return "", err
+	}
+	return owner.Hex(), nil
+}
+
+func (m *NFTContract) TokenURI(tokenID *big.Int) (string, error) {
+	return cachedView(m, "tokenURI", []any{tokenID}, func(opts *bind.CallOpts) (string, error) {
+		return m.nft.TokenURI(opts, tokenID)
+	})
+}
+
+func (m *NFTContract) BalanceOf(owner string) (*big.Int, error) {
+	return cachedView(m, "balanceOf", []any{owner}, func(opts *bind.CallOpts) (*big.Int, error) {
+		return m.nft.BalanceOf(opts, common.HexToAddress(owner))
+	})
+}
+
+func (m *NFTContract) TokenOfOwnerByIndex(owner string, index *big.Int) (*big.Int, error) {
+	return cachedView(m, "tokenOfOwnerByIndex", []any{owner, index}, func(opts *bind.CallOpts) (*big.Int, error) {
+		return m.nft.TokenOfOwnerByIndex(opts, common.HexToAddress(owner), index)
+	})
+}
+
+func (m *NFTContract) TokenByIndex(index *big.Int) (*big.Int, error) {
+	return cachedView(m, "tokenByIndex", []any{index}, func(opts *bind.CallOpts) (*big.Int, error) {
+		return m.nft.TokenByIndex(opts, index)
+	})
+}
+
+func (m *NFTContract) HashToID(uniqueHash string) (*big.Int, error) {
+	return cachedView(m, "hashToId", []any{uniqueHash}, func(opts *bind.CallOpts) (*big.Int, error) {
+		return m.nft.HashToId(opts, uniqueHash)
+	})
+}
+
+func (m *NFTContract) Name() (string, error) {
+	return cachedView(m, "name", nil, m.nft.Name)
+}
+
+func (m *NFTContract) Symbol() (string, error) {
+	return cachedView(m, "symbol", nil, m.nft.Symbol)
+}
+
+// cachedView returns the cached result of the view call or calls the contract and caches the result
+func cachedView[T any](m *NFTContract, method string, args []any, call func(opts *bind.CallOpts) (T, error)) (T, error) {
+	key := method + fmt.Sprint(args...)
+
+	if out, ok := m.views.get(key); ok {
+		return out.(T), nil
+	}
+
+	out, err := view(method, call)
+	if err != nil {
+		return out, err
+	}
+
+	m.views.set(key, out)
+	return out, nil
+}
+
+// view calls a view function of the contract at the latest block
+func view[T any](method string, call func(opts *bind.CallOpts) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	out, err := call(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		if strings.Contains(err.Error(), "execution reverted") {
+			return out, fmt.Errorf("%w: %s: %s", ErrCallReverted, method, err)
+		}
+		return out, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+
+	return out, nil
+}
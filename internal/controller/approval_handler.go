@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"nft_service/internal/contract"
+	"nft_service/internal/service"
+	"strconv"
+)
+
+type ApprovalHandler struct {
+	approvalService *service.ApprovalService
+}
+
+func NewApprovalHandler(approvalService *service.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{approvalService: approvalService}
+}
+
+// Approve
+// @Summary Approve an address for a token of the service wallet
+// @Description Sends approve(operator, token_id) from the service wallet. The zero address as operator revokes the approval.
+// @Tag Approvals
+// @Security AdminToken
+// @Param approval body ApproveRequest true "Token and the address to approve"
+// @Success 201 {object} domain.Approval "Approval transaction sent"
+// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 401 {object} ErrorResponse "Invalid admin token"
+// @Failure 422 {object} RevertResponse "Transaction would revert, decoded revert reason"
+// @Failure 500 {object} ErrorResponse "Failed to create approval"
+// @Failure 503 {object} ErrorResponse "Network fee exceeds configured ceiling or service wallet balance is insufficient"
+// @Router /api/approvals/approve [post]
+func (h *ApprovalHandler) Approve(c *gin.Context) {
+	var (
+		l       = slog.Default()
+		request = new(ApproveRequest)
+	)
+
+	if err := c.BindJSON(request); err != nil {
+		l.Error("invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid request",
+		})
+		return
+	}
+
+	approval, err := h.approvalService.Approve(c.GetInt("collectionId"), c.GetString("requestId"), request.TokenID, request.Operator)
+	if err != nil {
+		respondApprovalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, approval)
+}
+
+// SetApprovalForAll
+// @Summary Grant or revoke operator rights over all tokens of the service wallet
+// @Description Sends setApprovalForAll(operator, approved) from the service wallet.
+// @Tag Approvals
+// @Security AdminToken
+// @Param approval body SetApprovalForAllRequest true "Operator and whether it is approved"
+// @Success 201 {object} domain.Approval "Approval transaction sent"
+// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 401 {object} ErrorResponse "Invalid admin token"
+// @Failure 422 {object} RevertResponse "Transaction would revert, decoded revert reason"
+// @Failure 500 {object} ErrorResponse "Failed to create approval"
+// @Failure 503 {object} ErrorResponse "Network fee exceeds configured ceiling or service wallet balance is insufficient"
+// @Router /api/approvals/operator [post]
+func (h *ApprovalHandler) SetApprovalForAll(c *gin.Context) {
+	var (
+		l       = slog.Default()
+		request = new(SetApprovalForAllRequest)
+	)
+
+	if err := c.BindJSON(request); err != nil {
+		l.Error("invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid request",
+		})
+		return
+	}
+
+	approval, err := h.approvalService.SetApprovalForAll(c.GetInt("collectionId"), c.GetString("requestId"), request.Operator, *request.Approved)
+	if err != nil {
+		respondApprovalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, approval)
+}
+
+// List
+// @Summary Retrieve a paginated list of approvals
+// @Description Returns a list of the approval transactions of the collection with their status. By default, `limit` is set to 200, and `offset` is 0. The `limit` value must be between 1 and 500.
+// @Tag Approvals
+// @Security AdminToken
+// @Param offset query int false "Pagination offset, default 0"
+// @Param limit query int false "Number of pagination elements, default 200, max 500"
+// @Success 200 {array} domain.Approval "Successful response containing the list of approvals"
+// @Failure 400 {object} ErrorResponse "Invalid request parameters"
+// @Failure 401 {object} ErrorResponse "Invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/approvals/list [get]
+func (h *ApprovalHandler) List(c *gin.Context) {
+	var l = slog.Default()
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "200"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid limit, must be between 1 and 500",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid offset, must be greater than 0",
+		})
+		return
+	}
+
+	approvals, err := h.approvalService.ListApprovals(c.GetInt("collectionId"), limit, offset)
+	if err != nil {
+		l.Error("failed to list approvals", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "failed to list approvals",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, approvals)
+}
+
+func respondApprovalError(c *gin.Context, err error) {
+	l := slog.Default()
+	l.Error("failed to create approval", slog.Any("error", err))
+
+	var revertErr *contract.RevertError
+	switch {
+	case errors.As(err, &revertErr):
+		c.JSON(http.StatusUnprocessableEntity, RevertResponse{
+			RequestID: c.GetString("requestId"),
+			Error:     revertErr.Reason,
+			Revert:    revertErr,
+		})
+	case errors.Is(err, service.ErrInvalidArgument):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      err.Error(),
+		})
+	case errors.Is(err, contract.ErrFeeCeilingExceeded):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "network fee exceeds configured ceiling, try again later",
+		})
+	case errors.Is(err, contract.ErrInsufficientFunds):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "service wallet balance is insufficient, try again later",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "failed to create approval",
+		})
+	}
+}
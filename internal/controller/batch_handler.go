@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"nft_service/internal/service"
+	"strconv"
+)
+
+type BatchHandler struct {
+	batchService *service.BatchService
+}
+
+func NewBatchHandler(batchService *service.BatchService) *BatchHandler {
+	return &BatchHandler{batchService: batchService}
+}
+
+// Create
+// @Summary Mint a batch of NFT tokens
+// @Description Validates all items, stores the batch and mints the tokens in the background with contiguous nonces. The progress of the items is available by the returned batch id.
+// @Tag NFT Token
+// @Param batch body CreateBatchRequest true "Owners and media URLs of the tokens to mint"
+// @Success 202 {object} domain.MintBatch "Batch accepted"
+// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 500 {object} ErrorResponse "Failed to create batch"
+// @Router /api/tokens/batch [post]
+func (h *BatchHandler) Create(c *gin.Context) {
+	var (
+		l       = slog.Default()
+		request = new(CreateBatchRequest)
+	)
+
+	if err := c.BindJSON(request); err != nil {
+		l.Error("invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid request",
+		})
+		return
+	}
+
+	batch, err := h.batchService.CreateBatch(c.GetInt("collectionId"), request.Items)
+	if err != nil {
+		l.Error("failed to create batch", slog.Any("error", err))
+		if errors.Is(err, service.ErrInvalidArgument) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "failed to create batch",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, batch)
+}
+
+// Get
+// @Summary Retrieve the progress of a batch mint
+// @Description Returns the batch with the tx hash, token id and status of every item.
+// @Tag NFT Token
+// @Param id path int true "Batch id"
+// @Success 200 {object} domain.MintBatch "Batch with its items"
+// @Failure 400 {object} ErrorResponse "Invalid batch id"
+// @Failure 404 {object} ErrorResponse "Batch not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/tokens/batch/{id} [get]
+func (h *BatchHandler) Get(c *gin.Context) {
+	l := slog.Default()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid batch id",
+		})
+		return
+	}
+
+	batch, err := h.batchService.GetBatch(c.GetInt("collectionId"), id)
+	if err != nil {
+		l.Error("failed to get batch", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "failed to get batch",
+		})
+		return
+	}
+
+	if batch == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "batch not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"nft_service/internal/contract"
+	"nft_service/internal/service"
+)
+
+type ChainHandler struct {
+	chainService *service.ChainService
+}
+
+func NewChainHandler(chainService *service.ChainService) *ChainHandler {
+	return &ChainHandler{chainService: chainService}
+}
+
+// Info
+// @Summary Retrieve the contract name and symbol
+// @Description Returns the name and symbol of the NFT contract.
+// @Tag Chain
+// @Success 200 {object} ContractInfoResponse "Contract name and symbol"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/chain/info [get]
+func (h *ChainHandler) Info(c *gin.Context) {
+	info, err := h.chainService.Info(c.GetInt("collectionId"))
+	if err != nil {
+		respondChainError(c, err, "failed to get contract info")
+		return
+	}
+
+	c.JSON(http.StatusOK, ContractInfoResponse{Name: info.Name, Symbol: info.Symbol})
+}
+
+// Owner
+// @Summary Retrieve the owner of a token
+// @Description Returns the current owner of the token as reported by the contract.
+// @Tag Chain
+// @Param id path string true "Token id"
+// @Success 200 {object} OwnerResponse "Token owner"
+// @Failure 400 {object} ErrorResponse "Invalid token id"
+// @Failure 404 {object} ErrorResponse "Token does not exist"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/chain/tokens/{id}/owner [get]
+func (h *ChainHandler) Owner(c *gin.Context) {
+	tokenID := c.Param("id")
+
+	owner, err := h.chainService.OwnerOf(c.GetInt("collectionId"), tokenID)
+	if err != nil {
+		respondChainError(c, err, "failed to get token owner")
+		return
+	}
+
+	c.JSON(http.StatusOK, OwnerResponse{TokenID: tokenID, Owner: owner})
+}
+
+// TokenURI
+// @Summary Retrieve the metadata URI of a token
+// @Description Returns the token URI as reported by the contract.
+// @Tag Chain
+// @Param id path string true "Token id"
+// @Success 200 {object} TokenURIResponse "Token URI"
+// @Failure 400 {object} ErrorResponse "Invalid token id"
+// @Failure 404 {object} ErrorResponse "Token does not exist"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/chain/tokens/{id}/uri [get]
+func (h *ChainHandler) TokenURI(c *gin.Context) {
+	tokenID := c.Param("id")
+
+	uri, err := h.chainService.TokenURI(c.GetInt("collectionId"), tokenID)
+	if err != nil {
+		respondChainError(c, err, "failed to get token uri")
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenURIResponse{TokenID: tokenID, TokenURI: uri})
+}
+
+// TokenByIndex
+// @Summary Retrieve a token id by its global index
+// @Description Returns the token id stored at the given index of all tokens.
+// @Tag Chain
+// @Param index path string true "Global token index"
+// @Success 200 {object} TokenIDResponse "Token id"
+// @Failure 400 {object} ErrorResponse "Invalid index"
+// @Failure 404 {object} ErrorResponse "Index out of bounds"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/chain/indexes/{index}/token [get]
+func (h *ChainHandler) TokenByIndex(c *gin.Context) {
+	tokenID, err := h.chainService.TokenByIndex(c.GetInt("collectionId"), c.Param("index"))
+	if err != nil {
+		respondChainError(c, err, "failed to get token by index")
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenIDResponse{TokenID: tokenID.String()})
+}
+
+// Balance
+// @Summary Retrieve the number of tokens held by an address
+// @Description Returns the token balance of the owner as reported by the contract.
+// @Tag Chain
+// @Param address path string true "Owner address"
+// @Success 200 {object} BalanceResponse "Owner balance"
+// @Failure 400 {object} ErrorResponse "Invalid address"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/chain/owners/{address}/balance [get]
+func (h *ChainHandler) Balance(c *gin.Context) {
+	owner := c.Param("address")
+
+	balance, err := h.chainService.BalanceOf(c.GetInt("collectionId"), owner)
+	if err != nil {
+		respondChainError(c, err, "failed to get balance")
+		return
+	}
+
+	c.JSON(http.StatusOK, BalanceResponse{Owner: owner, Balance: balance.String()})
+}
+
+// TokenOfOwnerByIndex
+// @Summary Retrieve a token id of an owner by index
+// @Description Returns the token id stored at the given index of the owner's tokens.
+// @Tag Chain
+// @Param address path string true "Owner address"
+// @Param index path string true "Index in the owner's token list"
+// @Success 200 {object} TokenIDResponse "Token id"
+// @Failure 400 {object} ErrorResponse "Invalid address or index"
+// @Failure 404 {object} ErrorResponse "Index out of bounds"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/chain/owners/{address}/tokens/{index} [get]
+func (h *ChainHandler) TokenOfOwnerByIndex(c *gin.Context) {
+	tokenID, err := h.chainService.TokenOfOwnerByIndex(c.GetInt("collectionId"), c.Param("address"), c.Param("index"))
+	if err != nil {
+		respondChainError(c, err, "failed to get token of owner by index")
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenIDResponse{TokenID: tokenID.String()})
+}
+
+// HashToID
+// @Summary Retrieve a token id by its unique hash
+// @Description Returns the token id registered for the unique hash, 0 if the hash is unknown.
+// @Tag Chain
+// @Param unique_hash path string true "Unique hash of the token"
+// @Success 200 {object} TokenIDResponse "Token id"
+// @Failure 400 {object} ErrorResponse "Invalid unique hash"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/chain/hash/{unique_hash} [get]
+func (h *ChainHandler) HashToID(c *gin.Context) {
+	tokenID, err := h.chainService.HashToID(c.GetInt("collectionId"), c.Param("unique_hash"))
+	if err != nil {
+		respondChainError(c, err, "failed to get token id by hash")
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenIDResponse{TokenID: tokenID.String()})
+}
+
+// GetApproved
+// @Summary Retrieve the address approved for a token
+// @Description Returns the approved address of the token as reported by the contract, the zero address if there is none.
+// @Tag Chain
+// @Param id path string true "Token id"
+// @Success 200 {object} ApprovedResponse "Approved address"
+// @Failure 400 {object} ErrorResponse "Invalid token id"
+// @Failure 404 {object} ErrorResponse "Token does not exist"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/chain/tokens/{id}/approved [get]
+func (h *ChainHandler) GetApproved(c *gin.Context) {
+	tokenID := c.Param("id")
+
+	approved, err := h.chainService.GetApproved(c.GetInt("collectionId"), tokenID)
+	if err != nil {
+		respondChainError(c, err, "failed to get approved address")
+		return
+	}
+
+	c.JSON(http.StatusOK, ApprovedResponse{TokenID: tokenID, Approved: approved})
+}
+
+// IsApprovedForAll
+// @Summary Check whether an operator may manage all tokens of an owner
+// @Description Returns isApprovedForAll(owner, operator) as reported by the contract.
+// @Tag Chain
+// @Param address path string true "Owner address"
+// @Param operator path string true "Operator address"
+// @Success 200 {object} OperatorResponse "Operator approval"
+// @Failure 400 {object} ErrorResponse "Invalid address"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/chain/owners/{address}/operators/{operator} [get]
+func (h *ChainHandler) IsApprovedForAll(c *gin.Context) {
+	owner, operator := c.Param("address"), c.Param("operator")
+
+	approved, err := h.chainService.IsApprovedForAll(c.GetInt("collectionId"), owner, operator)
+	if err != nil {
+		respondChainError(c, err, "failed to get operator approval")
+		return
+	}
+
+	c.JSON(http.StatusOK, OperatorResponse{Owner: owner, Operator: operator, Approved: approved})
+}
+
+// respondChainError maps errors of contract view calls to a response
+func respondChainError(c *gin.Context, err error, message string) {
+	l := slog.Default()
+	l.Error(message, slog.Any("error", err))
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrInvalidArgument):
+		status, message = http.StatusBadRequest, err.Error()
+	case errors.Is(err, contract.ErrCallReverted):
+		status, message = http.StatusNotFound, "not found"
+	}
+
+	c.JSON(status, gin.H{
+		"request_id": c.GetString("requestId"),
+		"error":      message,
+	})
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"nft_service/internal/domain"
+	"nft_service/internal/service"
+	"strconv"
+)
+
+type CollectionHandler struct {
+	collectionService *service.CollectionService
+}
+
+func NewCollectionHandler(collectionService *service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{collectionService: collectionService}
+}
+
+// List
+// @Summary List the NFT collections
+// @Description Returns the collections managed by the service. Token, transfer and chain routes under /api/collections/{collection_id} are served by the contract of the collection, the routes without a collection id by the default collection.
+// @Tag Collections
+// @Param chain_id query int false "Only the collections deployed on the chain"
+// @Success 200 {array} domain.Collection "Collections"
+// @Failure 400 {object} ErrorResponse "Invalid chain id"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/collections/list [get]
+func (h *CollectionHandler) List(c *gin.Context) {
+	chainID, err := strconv.ParseInt(c.DefaultQuery("chain_id", "0"), 10, 64)
+	if err != nil || chainID < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid chain_id, must be a positive integer",
+		})
+		return
+	}
+
+	collections, err := h.collectionService.List(chainID)
+	if err != nil {
+		slog.Default().Error("failed to list collections", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "failed to list collections",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, collections)
+}
+
+// Create
+// @Summary Register an NFT collection
+// @Description Registers a contract deployed from the same source as the default collection. The service wallet must hold the minter role in it.
+// @Tag Admin
+// @Security AdminToken
+// @Param collection body CreateCollectionRequest true "Contract of the collection"
+// @Success 201 {object} domain.Collection "Collection registered"
+// @Failure 400 {object} ErrorResponse "Invalid collection"
+// @Failure 401 {object} ErrorResponse "Invalid admin token"
+// @Failure 500 {object} ErrorResponse "Failed to register collection"
+// @Router /api/admin/collections [post]
+func (h *CollectionHandler) Create(c *gin.Context) {
+	var (
+		l       = slog.Default()
+		request = new(domain.Collection)
+	)
+
+	if err := c.BindJSON(request); err != nil {
+		l.Error("invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid request",
+		})
+		return
+	}
+
+	collection, err := h.collectionService.Create(request)
+	if err != nil {
+		l.Error("failed to create collection", slog.Any("error", err))
+		if errors.Is(err, service.ErrInvalidArgument) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "failed to create collection",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, collection)
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"nft_service/internal/service"
+	"strconv"
+)
+
+type DeadLetterHandler struct {
+	deadLetterService *service.DeadLetterService
+}
+
+func NewDeadLetterHandler(deadLetterService *service.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{deadLetterService: deadLetterService}
+}
+
+// List
+// @Summary Inspect a dead letter queue
+// @Description Returns the messages of the dead letter queue of a receipt queue without removing them. A message is dead-lettered after the last attempt to track its transaction, the row of the transaction is marked unknown.
+// @Tag Admin
+// @Security AdminToken
+// @Param queue path string true "Receipt queue, e.g. token_queue"
+// @Param limit query int false "Number of messages, default 20, max 100"
+// @Success 200 {array} service.DeadLetter "Dead-lettered messages"
+// @Failure 400 {object} ErrorResponse "Unknown queue or invalid limit"
+// @Failure 401 {object} ErrorResponse "Invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/admin/dlq/{queue} [get]
+func (h *DeadLetterHandler) List(c *gin.Context) {
+	limit, ok := deadLetterLimit(c)
+	if !ok {
+		return
+	}
+
+	deadLetters, err := h.deadLetterService.List(c.Param("queue"), limit)
+	if err != nil {
+		respondDeadLetterError(c, err, "failed to list dead letters")
+		return
+	}
+
+	c.JSON(http.StatusOK, deadLetters)
+}
+
+// Replay
+// @Summary Replay a dead letter queue
+// @Description Moves the oldest messages of the dead letter queue back to the receipt queue with fresh attempts. Rows marked unknown are tracked again once their transaction is mined.
+// @Tag Admin
+// @Security AdminToken
+// @Param queue path string true "Receipt queue, e.g. token_queue"
+// @Param limit query int false "Number of messages, default 20, max 100"
+// @Success 200 {object} ReplayResponse "Replayed messages"
+// @Failure 400 {object} ErrorResponse "Unknown queue or invalid limit"
+// @Failure 401 {object} ErrorResponse "Invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/admin/dlq/{queue}/replay [post]
+func (h *DeadLetterHandler) Replay(c *gin.Context) {
+	limit, ok := deadLetterLimit(c)
+	if !ok {
+		return
+	}
+
+	queue := c.Param("queue")
+
+	replayed, err := h.deadLetterService.Replay(queue, limit)
+	if err != nil {
+		respondDeadLetterError(c, err, "failed to replay dead letters")
+		return
+	}
+
+	slog.Default().Info("dead letters replayed", slog.String("queue", queue), slog.Int("replayed", replayed))
+
+	c.JSON(http.StatusOK, ReplayResponse{Queue: queue, Replayed: replayed})
+}
+
+func deadLetterLimit(c *gin.Context) (int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid limit",
+		})
+		return 0, false
+	}
+	return limit, true
+}
+
+func respondDeadLetterError(c *gin.Context, err error, message string) {
+	if errors.Is(err, service.ErrInvalidArgument) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	slog.Default().Error(message, slog.Any("error", err))
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"request_id": c.GetString("requestId"),
+		"error":      message,
+	})
+}
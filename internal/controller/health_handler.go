@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// HealthChecker reports whether a dependency of the service is available
+type HealthChecker interface {
+	Connected() bool
+}
+
+type HealthHandler struct {
+	checks map[string]HealthChecker
+}
+
+func NewHealthHandler(checks map[string]HealthChecker) *HealthHandler {
+	return &HealthHandler{checks: checks}
+}
+
+// Health
+// @Summary      Health of the dependencies
+// @Description  Reports the connection state of the dependencies that reconnect on their own. The status is 503 while any of them is disconnected.
+// @Tag         Health
+// @Success      200  {object}  HealthResponse  "All dependencies are connected"
+// @Failure      503  {object}  HealthResponse  "A dependency is disconnected"
+// @Router       /api/health [get]
+func (h *HealthHandler) Health(c *gin.Context) {
+	response := HealthResponse{Status: "ok", Dependencies: make(map[string]string, len(h.checks))}
+
+	for name, check := range h.checks {
+		if check.Connected() {
+			response.Dependencies[name] = "connected"
+			continue
+		}
+		response.Dependencies[name] = "disconnected"
+		response.Status = "degraded"
+	}
+
+	status := http.StatusOK
+	if response.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, response)
+}
@@ -1,9 +1,14 @@
 package controller
 
 import (
+	"crypto/subtle"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/xid"
 	"log/slog"
+	"net/http"
+	"nft_service/internal/service"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -60,3 +65,53 @@ func LoggerMiddleware() gin.HandlerFunc {
 		l.Info("request")
 	}
 }
+
+// AdminMiddleware lets through requests with the admin bearer token. The admin endpoints are disabled
+// when no token is configured.
+func AdminMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      "admin api is disabled",
+			})
+			return
+		}
+
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      "unauthorized",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CollectionMiddleware resolves the collection of the request from the collection_id path parameter.
+// Routes without the parameter are served by the default collection.
+func CollectionMiddleware(collections *service.CollectionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		param := c.Param("collection_id")
+		if param == "" {
+			c.Set("collectionId", collections.DefaultID())
+			c.Next()
+			return
+		}
+
+		id, err := strconv.Atoi(param)
+		if err != nil || !collections.Exists(id) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      "collection not found",
+			})
+			return
+		}
+
+		c.Set("collectionId", id)
+		c.Next()
+	}
+}
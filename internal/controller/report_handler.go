@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"nft_service/internal/service"
+	"strconv"
+	"time"
+)
+
+const reportDateLayout = "2006-01-02"
+
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// Gas
+// @Summary Report the gas spent on mints and transfers
+// @Description Aggregates the fees of the final mint and transfer transactions sent by the service per day, per operation and per owner. Failed and cancelled transactions are included, transfers made outside the service are not.
+// @Tag Reports
+// @Param chain_id query int false "Chain of the transactions, default the chain of the default collection"
+// @Param from query string false "First day, YYYY-MM-DD UTC, default the first day of the current month"
+// @Param to query string false "Last day, YYYY-MM-DD UTC, default today"
+// @Success 200 {object} domain.GasReport "Gas report"
+// @Failure 400 {object} ErrorResponse "Invalid chain id or period"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/reports/gas [get]
+func (h *ReportHandler) Gas(c *gin.Context) {
+	var (
+		l     = slog.Default()
+		today = time.Now().UTC().Truncate(24 * time.Hour)
+	)
+
+	chainID, err := strconv.ParseInt(c.DefaultQuery("chain_id", "0"), 10, 64)
+	if err != nil || chainID < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid chain_id, must be a positive integer",
+		})
+		return
+	}
+
+	from, err := time.Parse(reportDateLayout, c.DefaultQuery("from", today.AddDate(0, 0, 1-today.Day()).Format(reportDateLayout)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid from, must be a YYYY-MM-DD date",
+		})
+		return
+	}
+
+	to, err := time.Parse(reportDateLayout, c.DefaultQuery("to", today.Format(reportDateLayout)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid to, must be a YYYY-MM-DD date",
+		})
+		return
+	}
+
+	// the last day is included
+	report, err := h.reportService.GasReport(chainID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidArgument) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      err.Error(),
+			})
+			return
+		}
+		l.Error("failed to build gas report", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "failed to build gas report",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
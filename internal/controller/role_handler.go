@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"nft_service/internal/contract"
+	"nft_service/internal/service"
+)
+
+type RoleHandler struct {
+	roleService *service.RoleService
+}
+
+func NewRoleHandler(roleService *service.RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+// Members
+// @Summary List the members of a role
+// @Description Returns the accounts holding the role, reconstructed from final RoleGranted and RoleRevoked events, and the role that administers it. The role is a name like MINTER_ROLE or a 0x prefixed bytes32 id.
+// @Tag Admin
+// @Security AdminToken
+// @Param role path string true "Role name or id"
+// @Success 200 {object} service.RoleMembers "Role members"
+// @Failure 400 {object} ErrorResponse "Invalid role"
+// @Failure 401 {object} ErrorResponse "Invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/admin/roles/{role}/members [get]
+func (h *RoleHandler) Members(c *gin.Context) {
+	members, err := h.roleService.Members(c.GetInt("collectionId"), c.Param("role"))
+	if err != nil {
+		respondChainError(c, err, "failed to list role members")
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// HasRole
+// @Summary Check whether an account holds a role
+// @Description Returns hasRole(role, account) as reported by the contract.
+// @Tag Admin
+// @Security AdminToken
+// @Param role path string true "Role name or id"
+// @Param address path string true "Account address"
+// @Success 200 {object} HasRoleResponse "Role check"
+// @Failure 400 {object} ErrorResponse "Invalid role or address"
+// @Failure 401 {object} ErrorResponse "Invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/admin/roles/{role}/members/{address} [get]
+func (h *RoleHandler) HasRole(c *gin.Context) {
+	role, account := c.Param("role"), c.Param("address")
+
+	hasRole, err := h.roleService.HasRole(c.GetInt("collectionId"), role, account)
+	if err != nil {
+		respondChainError(c, err, "failed to check role")
+		return
+	}
+
+	c.JSON(http.StatusOK, HasRoleResponse{Role: role, Account: account, HasRole: hasRole})
+}
+
+// Grant
+// @Summary Grant a role to an account
+// @Description Sends grantRole(role, account) from the service wallet, which must hold the admin role of the role.
+// @Tag Admin
+// @Security AdminToken
+// @Param role path string true "Role name or id"
+// @Param account body RoleAccountRequest true "Account to grant the role to"
+// @Success 201 {object} TxHashResponse "Transaction sent"
+// @Failure 400 {object} ErrorResponse "Invalid role or address"
+// @Failure 401 {object} ErrorResponse "Invalid admin token"
+// @Failure 422 {object} RevertResponse "Transaction would revert, decoded revert reason"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Network fee exceeds configured ceiling or service wallet balance is insufficient"
+// @Router /api/admin/roles/{role}/grant [post]
+func (h *RoleHandler) Grant(c *gin.Context) {
+	h.send(c, h.roleService.Grant)
+}
+
+// Revoke
+// @Summary Revoke a role from an account
+// @Description Sends revokeRole(role, account) from the service wallet, which must hold the admin role of the role.
+// @Tag Admin
+// @Security AdminToken
+// @Param role path string true "Role name or id"
+// @Param account body RoleAccountRequest true "Account to revoke the role from"
+// @Success 201 {object} TxHashResponse "Transaction sent"
+// @Failure 400 {object} ErrorResponse "Invalid role or address"
+// @Failure 401 {object} ErrorResponse "Invalid admin token"
+// @Failure 422 {object} RevertResponse "Transaction would revert, decoded revert reason"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Network fee exceeds configured ceiling or service wallet balance is insufficient"
+// @Router /api/admin/roles/{role}/revoke [post]
+func (h *RoleHandler) Revoke(c *gin.Context) {
+	h.send(c, h.roleService.Revoke)
+}
+
+func (h *RoleHandler) send(c *gin.Context, send func(collectionID int, role, account string) (string, error)) {
+	var (
+		l       = slog.Default()
+		request = new(RoleAccountRequest)
+	)
+
+	if err := c.BindJSON(request); err != nil {
+		l.Error("invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid request",
+		})
+		return
+	}
+
+	txHash, err := send(c.GetInt("collectionId"), c.Param("role"), request.Account)
+	if err != nil {
+		l.Error("failed to send role transaction", slog.Any("error", err))
+
+		var revertErr *contract.RevertError
+		switch {
+		case errors.As(err, &revertErr):
+			c.JSON(http.StatusUnprocessableEntity, RevertResponse{
+				RequestID: c.GetString("requestId"),
+				Error:     revertErr.Reason,
+				Revert:    revertErr,
+			})
+		case errors.Is(err, service.ErrInvalidArgument):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      err.Error(),
+			})
+		case errors.Is(err, contract.ErrFeeCeilingExceeded):
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      "network fee exceeds configured ceiling, try again later",
+			})
+		case errors.Is(err, contract.ErrInsufficientFunds):
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      "service wallet balance is insufficient, try again later",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      "failed to send role transaction",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, TxHashResponse{TxHash: txHash})
+}
@@ -1,5 +1,10 @@
 package controller
 
+import (
+	"nft_service/internal/contract"
+	"nft_service/internal/domain"
+)
+
 type CreateTokenRequest struct {
 	Owner    string `json:"owner"`
 	MediaUrl string `json:"media_url"`
@@ -13,8 +18,94 @@ type ErrorResponse struct {
 	RequestID string `json:"request_id"`
 	Error     string `json:"error"`
 }
+type RevertResponse struct {
+	RequestID string                `json:"request_id"`
+	Error     string                `json:"error"`
+	Revert    *contract.RevertError `json:"revert"`
+}
+
 type CreateTransferRequest struct {
 	From    string `json:"from_address"`
 	To      string `json:"to_address"`
 	TokenId string `json:"token_id"`
 }
+
+type ContractInfoResponse struct {
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+}
+
+type OwnerResponse struct {
+	TokenID string `json:"token_id"`
+	Owner   string `json:"owner"`
+}
+
+type TokenURIResponse struct {
+	TokenID  string `json:"token_id"`
+	TokenURI string `json:"token_uri"`
+}
+
+type BalanceResponse struct {
+	Owner   string `json:"owner"`
+	Balance string `json:"balance"`
+}
+
+type TokenIDResponse struct {
+	TokenID string `json:"token_id"`
+}
+
+type CreateBatchRequest struct {
+	Items []*domain.Token `json:"items" binding:"required,dive"`
+}
+
+type ApproveRequest struct {
+	TokenID  string `json:"token_id" binding:"required"`
+	Operator string `json:"operator" binding:"required"`
+}
+
+type SetApprovalForAllRequest struct {
+	Operator string `json:"operator" binding:"required"`
+	Approved *bool  `json:"approved" binding:"required"`
+}
+
+type ApprovedResponse struct {
+	TokenID  string `json:"token_id"`
+	Approved string `json:"approved"`
+}
+
+type OperatorResponse struct {
+	Owner    string `json:"owner"`
+	Operator string `json:"operator"`
+	Approved bool   `json:"approved"`
+}
+
+type RoleAccountRequest struct {
+	Account string `json:"account" binding:"required"`
+}
+
+type HasRoleResponse struct {
+	Role    string `json:"role"`
+	Account string `json:"account"`
+	HasRole bool   `json:"has_role"`
+}
+
+type TxHashResponse struct {
+	TxHash string `json:"tx_hash"`
+}
+
+type CreateCollectionRequest struct {
+	Address string `json:"address"`
+	ChainID int64  `json:"chain_id"`
+	ABIRef  string `json:"abi_ref"`
+	Name    string `json:"name"`
+}
+
+type ReplayResponse struct {
+	Queue    string `json:"queue"`
+	Replayed int    `json:"replayed"`
+}
+
+type HealthResponse struct {
+	Status       string            `json:"status"`
+	Dependencies map[string]string `json:"dependencies"`
+}
@@ -1,10 +1,12 @@
 package controller
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"log/slog"
 	"math/big"
 	"net/http"
+	"nft_service/internal/contract"
 	"nft_service/internal/domain"
 	"nft_service/internal/service"
 	"strconv"
@@ -25,7 +27,9 @@ func NewTokenHandler(tokenService *service.TokenService) *TokenHandler {
 // @Param token body CreateTokenRequest true "Data required to create the NFT token"
 // @Success 201 {object} domain.Token "Successfully created token"
 // @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 422 {object} RevertResponse "Transaction would revert, decoded revert reason"
 // @Failure 500 {object} ErrorResponse "Failed to create token"
+// @Failure 503 {object} ErrorResponse "Network fee exceeds configured ceiling or service wallet balance is insufficient"
 // @Router /api/tokens/create [post]
 func (h *TokenHandler) Create(c *gin.Context) {
 
@@ -43,9 +47,34 @@ func (h *TokenHandler) Create(c *gin.Context) {
 		return
 	}
 
-	token, err := h.tokenService.CreateToken(request)
+	request.RequestID = c.GetString("requestId")
+
+	token, err := h.tokenService.CreateToken(c.GetInt("collectionId"), request)
 	if err != nil {
 		l.Error("failed to generate token", slog.Any("error", err))
+		var revertErr *contract.RevertError
+		if errors.As(err, &revertErr) {
+			c.JSON(http.StatusUnprocessableEntity, RevertResponse{
+				RequestID: c.GetString("requestId"),
+				Error:     revertErr.Reason,
+				Revert:    revertErr,
+			})
+			return
+		}
+		if errors.Is(err, contract.ErrFeeCeilingExceeded) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      "network fee exceeds configured ceiling, try again later",
+			})
+			return
+		}
+		if errors.Is(err, contract.ErrInsufficientFunds) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      "service wallet balance is insufficient, try again later",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"request_id": c.GetString("requestId"),
 			"error":      "failed to generate token",
@@ -62,6 +91,7 @@ func (h *TokenHandler) Create(c *gin.Context) {
 // @Tag NFT Token
 // @Param offset query int false "Pagination offset, default 0"
 // @Param limit query int false "Number of pagination elements, default 200, max 500"
+// @Param chain_id query int false "Only the tokens on the chain, without a collection id in the path the tokens of every collection on the chain"
 // @Success 200 {array} domain.Token "Successful response containing the list of tokens"
 // @Failure 400 {object} ErrorResponse "Invalid request parameters"
 // @Failure 500 {object} ErrorResponse "Internal server error"
@@ -112,7 +142,23 @@ func (h *TokenHandler) List(c *gin.Context) {
 		return
 	}
 
-	tokens, err := h.tokenService.ListTokens(limit, offset)
+	chainID, err := strconv.ParseInt(c.DefaultQuery("chain_id", "0"), 10, 64)
+	if err != nil || chainID < 0 {
+		l.Error("invalid chain id", slog.String("chain_id", c.Query("chain_id")), slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, &gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid chain_id, must be a positive integer",
+		})
+		return
+	}
+
+	collectionID := c.GetInt("collectionId")
+	if chainID != 0 && c.Param("collection_id") == "" {
+		// the route of the default collection lists every collection of the chain
+		collectionID = 0
+	}
+
+	tokens, err := h.tokenService.ListTokens(collectionID, chainID, limit, offset)
 	if err != nil {
 		l.Error("failed to list tokens", slog.Any("error", err))
 
@@ -143,7 +189,7 @@ func (h *TokenHandler) Total(c *gin.Context) {
 
 	c.Header("Content-Type", "application/json")
 
-	totalSupply, err = h.tokenService.TotalSupply()
+	totalSupply, err = h.tokenService.TotalSupply(c.GetInt("collectionId"))
 	if err != nil {
 		l.Error("failed to get total supply", slog.Any("error", err))
 
@@ -178,7 +224,7 @@ func (h *TokenHandler) ExactTotal(c *gin.Context) {
 
 	c.Header("Content-Type", "application/json")
 
-	totalSupply, err = h.tokenService.ExactTotalSupply()
+	totalSupply, err = h.tokenService.ExactTotalSupply(c.GetInt("collectionId"))
 	if err != nil {
 		l.Error("failed to get total supply", slog.Any("error", err))
 
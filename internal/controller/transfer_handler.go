@@ -1,9 +1,11 @@
 package controller
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"log/slog"
 	"net/http"
+	"nft_service/internal/contract"
 	"nft_service/internal/domain"
 	"nft_service/internal/service"
 	"strconv"
@@ -24,7 +26,9 @@ func NewTransferHandler(transferService *service.TransferService) *TransferHandl
 // @Param token body CreateTransferRequest true "Data required to create the transfer NFT token"
 // @Success 201 {object} domain.Transfer "Successfully created transfer"
 // @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 422 {object} RevertResponse "Transaction would revert, decoded revert reason"
 // @Failure 500 {object} ErrorResponse "Failed to create transfer"
+// @Failure 503 {object} ErrorResponse "Network fee exceeds configured ceiling or service wallet balance is insufficient"
 // @Router /api/transfers/create [post]
 func (h *TransferHandler) Create(c *gin.Context) {
 
@@ -42,9 +46,34 @@ func (h *TransferHandler) Create(c *gin.Context) {
 		return
 	}
 
-	token, err := h.transferService.CreateTransfer(request)
+	request.RequestID = c.GetString("requestId")
+
+	token, err := h.transferService.CreateTransfer(c.GetInt("collectionId"), request)
 	if err != nil {
 		l.Error("failed to generate transfer", slog.Any("error", err))
+		var revertErr *contract.RevertError
+		if errors.As(err, &revertErr) {
+			c.JSON(http.StatusUnprocessableEntity, RevertResponse{
+				RequestID: c.GetString("requestId"),
+				Error:     revertErr.Reason,
+				Revert:    revertErr,
+			})
+			return
+		}
+		if errors.Is(err, contract.ErrFeeCeilingExceeded) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      "network fee exceeds configured ceiling, try again later",
+			})
+			return
+		}
+		if errors.Is(err, contract.ErrInsufficientFunds) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"request_id": c.GetString("requestId"),
+				"error":      "service wallet balance is insufficient, try again later",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"request_id": c.GetString("requestId"),
 			"error":      "failed to generate transfer",
@@ -61,6 +90,7 @@ func (h *TransferHandler) Create(c *gin.Context) {
 // @Tag Transfers
 // @Param offset query int false "Pagination offset, default 0"
 // @Param limit query int false "Number of pagination elements, default 200, max 500"
+// @Param chain_id query int false "Only the transfers on the chain, without a collection id in the path the transfers of every collection on the chain"
 // @Success 200 {array} domain.Transfer "Successful response containing the list of transfers"
 // @Failure 400 {object} ErrorResponse "Invalid request parameters"
 // @Failure 500 {object} ErrorResponse "Internal server error"
@@ -111,7 +141,23 @@ func (h *TransferHandler) List(c *gin.Context) {
 		return
 	}
 
-	transfers, err := h.transferService.ListTransfer(limit, offset)
+	chainID, err := strconv.ParseInt(c.DefaultQuery("chain_id", "0"), 10, 64)
+	if err != nil || chainID < 0 {
+		l.Error("invalid chain id", slog.String("chain_id", c.Query("chain_id")), slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, &gin.H{
+			"request_id": c.GetString("requestId"),
+			"error":      "invalid chain_id, must be a positive integer",
+		})
+		return
+	}
+
+	collectionID := c.GetInt("collectionId")
+	if chainID != 0 && c.Param("collection_id") == "" {
+		// the route of the default collection lists every collection of the chain
+		collectionID = 0
+	}
+
+	transfers, err := h.transferService.ListTransfer(collectionID, chainID, limit, offset)
 	if err != nil {
 		l.Error("failed to list transfers", slog.Any("error", err))
 
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+)
+
+var ethereumAddressRegexp = regexp.MustCompile(ethereumAddressExpression)
+
+// ValidateAddress checks that the value is a hex encoded ethereum address
+func ValidateAddress(address string) error {
+	if !ethereumAddressRegexp.MatchString(address) {
+		return errors.New("invalid address " + address)
+	}
+	return nil
+}
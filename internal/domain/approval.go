@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+	"time"
+)
+
+const (
+	ApprovalKindToken    = "token"
+	ApprovalKindOperator = "operator"
+
+	ApprovalStatusPending    = "pending"
+	ApprovalStatusConfirming = "confirming"
+	ApprovalStatusSuccess    = "success"
+	ApprovalStatusFailed     = "failed"
+	ApprovalStatusCancelled  = "cancelled"
+	ApprovalStatusUnknown    = "unknown" // the receipt could not be tracked, the message is in the dead letter queue
+)
+
+type ApprovalRepository interface {
+	UpdateStatus(status, txHash string) error
+	ReplaceTxHash(oldTxHash, newTxHash string) error
+	MarkConfirming(txHash string, blockNumber uint64, blockHash string) (previousBlockHash string, err error)
+	RollbackToPending(txHash string) error
+	List(collectionID, limit, offset int) ([]Approval, error)
+}
+
+// Approval is an approve (Kind token) or setApprovalForAll (Kind operator) transaction sent by the service wallet
+type Approval struct {
+	ID           int       `json:"id"`
+	CollectionID int       `json:"collection_id"`
+	Kind         string    `json:"kind"`
+	TokenID      string    `json:"token_id,omitempty"`
+	Operator     string    `json:"operator"`
+	Approved     bool      `json:"approved"`
+	TxHash       string    `json:"tx_hash"`
+	Status       string    `json:"status"`
+	BlockNumber  uint64    `json:"block_number,omitempty"`
+	BlockHash    string    `json:"block_hash,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	RequestID    string    `json:"-"` // request that created the approval, carried by its queue message
+}
+
+func (a *Approval) ValidateToCreate() error {
+	if err := ValidateAddress(a.Operator); err != nil {
+		return err
+	}
+
+	switch a.Kind {
+	case ApprovalKindToken:
+		if _, ok := new(big.Int).SetString(a.TokenID, 10); !ok {
+			return errors.New("invalid token id")
+		}
+	case ApprovalKindOperator:
+		if a.TokenID != "" {
+			return errors.New("operator approval must not have a token id")
+		}
+	default:
+		return errors.New("invalid approval kind " + a.Kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestApprovalValidateToCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		approval  Approval
+		expectErr bool
+	}{
+		{
+			name: "token approval",
+			approval: Approval{
+				Kind:     ApprovalKindToken,
+				TokenID:  "123",
+				Operator: "0xC92f65c05ccdeF650fe1fdeC0221E5f993ea8956",
+			},
+			expectErr: false,
+		},
+		{
+			name: "operator approval",
+			approval: Approval{
+				Kind:     ApprovalKindOperator,
+				Operator: "0xC92f65c05ccdeF650fe1fdeC0221E5f993ea8956",
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid operator",
+			approval: Approval{
+				Kind:     ApprovalKindOperator,
+				Operator: "invalid_address",
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid token id",
+			approval: Approval{
+				Kind:     ApprovalKindToken,
+				TokenID:  "abc",
+				Operator: "0xC92f65c05ccdeF650fe1fdeC0221E5f993ea8956",
+			},
+			expectErr: true,
+		},
+		{
+			name: "operator approval with token id",
+			approval: Approval{
+				Kind:     ApprovalKindOperator,
+				TokenID:  "123",
+				Operator: "0xC92f65c05ccdeF650fe1fdeC0221E5f993ea8956",
+			},
+			expectErr: true,
+		},
+		{
+			name: "unknown kind",
+			approval: Approval{
+				Kind:     "other",
+				Operator: "0xC92f65c05ccdeF650fe1fdeC0221E5f993ea8956",
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.approval.ValidateToCreate()
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
+			}
+		})
+	}
+}
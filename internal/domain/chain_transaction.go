@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"math/big"
+	"time"
+)
+
+const (
+	ChainTxKindMint      = "mint"
+	ChainTxKindTransfer  = "transfer"
+	ChainTxKindApproval  = "approval"
+	ChainTxKindRole      = "role"
+	ChainTxKindNonceFill = "nonce_fill" // no-op self-transfer taking the nonce of a transaction that was never sent
+
+	ChainTxStatusSigned   = "signed" // stored, not broadcast yet
+	ChainTxStatusPending  = "pending"
+	ChainTxStatusMined    = "mined"
+	ChainTxStatusReplaced = "replaced"
+	ChainTxStatusDropped  = "dropped"
+)
+
+type ChainTransactionRepository interface {
+	CreateSigned(tx *ChainTransaction) error
+	MarkBroadcast(txHash string) error
+	CountBroadcastAttempt(txHash string) (int, error)
+	Discard(txHash string) error
+	GetByHash(txHash string) (*ChainTransaction, error)
+	ListSigned(chainID int64, olderThan time.Time, limit int) ([]*ChainTransaction, error)
+	ListStuck(chainID int64, olderThan time.Time, limit int) ([]*ChainTransaction, error)
+	ListByNonce(chainID int64, fromAddress string, nonce uint64) ([]*ChainTransaction, error)
+	UpdateStatus(status, txHash string) error
+	MarkReplaced(txHash, replacedBy string) error
+}
+
+// ChainTransaction is a transaction signed by the service wallet, it is stored before it is broadcast.
+// Kind is the operation of the nfts/transfers row it belongs to; a cancel keeps the kind of the transaction it cancels.
+type ChainTransaction struct {
+	ID                int       `json:"id"`
+	ChainID           int64     `json:"chain_id"`
+	TxHash            string    `json:"tx_hash"`
+	Kind              string    `json:"kind"`
+	IsCancel          bool      `json:"is_cancel"`
+	FromAddress       string    `json:"from_address"`
+	ToAddress         string    `json:"to_address"`
+	Nonce             uint64    `json:"nonce"`
+	Data              []byte    `json:"-"`
+	RawTx             []byte    `json:"-"` // signed transaction
+	Intent            *TxIntent `json:"intent,omitempty"`
+	GasLimit          uint64    `json:"gas_limit"`
+	GasTipCap         *big.Int  `json:"gas_tip_cap"`
+	GasFeeCap         *big.Int  `json:"gas_fee_cap"`
+	Status            string    `json:"status"`
+	ReplacedBy        string    `json:"replaced_by,omitempty"`
+	Replaces          string    `json:"-"` // hash of the transaction a new replacement replaces, not stored
+	SpeedUps          int       `json:"speed_ups"`
+	BroadcastAttempts int       `json:"broadcast_attempts"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TxIntent is the row a transaction is sent for. It is stored with the signed transaction in one database
+// transaction, so every broadcast transaction has its row. Replacements carry the intent of the transaction
+// they replace, role transactions and nonce fillers have no intent.
+type TxIntent struct {
+	Token     *Token    `json:"token,omitempty"`
+	Transfer  *Transfer `json:"transfer,omitempty"`
+	Approval  *Approval `json:"approval,omitempty"`
+	RequestID string    `json:"request_id,omitempty"` // request that created the row, the rows do not store it
+}
+
+// Row returns the id of the stored row of the intent and the request that created it
+func (i *TxIntent) Row() (id int, requestID string) {
+	switch {
+	case i.Token != nil:
+		id, requestID = i.Token.ID, i.Token.RequestID
+	case i.Transfer != nil:
+		id, requestID = i.Transfer.ID, i.Transfer.RequestID
+	case i.Approval != nil:
+		id, requestID = i.Approval.ID, i.Approval.RequestID
+	}
+	if requestID == "" {
+		requestID = i.RequestID
+	}
+	return id, requestID
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type CollectionRepository interface {
+	Create(collection *Collection) error
+	SeedDefault(collection *Collection) error
+	Get(id int) (*Collection, error)
+	List(chainID int64) ([]*Collection, error)
+}
+
+// Collection is an NFT contract managed by the service
+type Collection struct {
+	ID        int       `json:"id"`
+	Address   string    `json:"address" binding:"required"`
+	ChainID   int64     `json:"chain_id" binding:"required"`
+	ABIRef    string    `json:"abi_ref,omitempty"`
+	Name      string    `json:"name" binding:"required"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *Collection) ValidateToCreate() error {
+	if err := ValidateAddress(c.Address); err != nil {
+		return err
+	}
+
+	if c.ChainID <= 0 {
+		return errors.New("invalid chain id")
+	}
+
+	if c.Name == "" || len(c.Name) > 255 {
+		return errors.New("invalid name, must be non-empty and less than 255 characters")
+	}
+
+	return nil
+}
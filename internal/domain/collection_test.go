@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestCollectionValidateToCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		collection Collection
+		expectErr  bool
+	}{
+		{
+			name: "valid collection",
+			collection: Collection{
+				Address: "0x399c1448e0F34aB3722e3aFDd21301Ca6cFF4c4a",
+				ChainID: 11155111,
+				Name:    "Test collection",
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid address",
+			collection: Collection{
+				Address: "invalid_address",
+				ChainID: 11155111,
+				Name:    "Test collection",
+			},
+			expectErr: true,
+		},
+		{
+			name: "missing chain id",
+			collection: Collection{
+				Address: "0x399c1448e0F34aB3722e3aFDd21301Ca6cFF4c4a",
+				Name:    "Test collection",
+			},
+			expectErr: true,
+		},
+		{
+			name: "empty name",
+			collection: Collection{
+				Address: "0x399c1448e0F34aB3722e3aFDd21301Ca6cFF4c4a",
+				ChainID: 11155111,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.collection.ValidateToCreate()
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
+			}
+		})
+	}
+}
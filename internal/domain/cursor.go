@@ -0,0 +1,6 @@
+package domain
+
+type CursorRepository interface {
+	GetCursor(name string) (blockNumber uint64, found bool, err error)
+	SaveCursor(name string, blockNumber uint64) error
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// EnvelopeVersion is the schema version of the envelopes written by this version of the service
+const EnvelopeVersion = 1
+
+const (
+	MessageTypeMintSent     = "mint.sent"
+	MessageTypeTransferSent = "transfer.sent"
+	MessageTypeApprovalSent = "approval.sent"
+)
+
+// ErrInvalidMessage is returned for a queue message that can never be handled, it is dead-lettered without retries
+var ErrInvalidMessage = errors.New("invalid message")
+
+var txHashExpression = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+// messageTypes maps the kind of a chain transaction to the type of the message tracking it
+var messageTypes = map[string]string{
+	ChainTxKindMint:     MessageTypeMintSent,
+	ChainTxKindTransfer: MessageTypeTransferSent,
+	ChainTxKindApproval: MessageTypeApprovalSent,
+}
+
+// Envelope is the body of the messages of the receipt queues.
+// Version 0 is the bare JSON string with the tx hash written by earlier versions, it is still decoded.
+type Envelope struct {
+	Type      string    `json:"type"`
+	Version   int       `json:"version"`
+	EntityID  int       `json:"entity_id,omitempty"` // id of the nfts, transfers or approvals row, 0 when unknown
+	TxHash    string    `json:"tx_hash"`
+	ChainID   int64     `json:"chain_id"`
+	RequestID string    `json:"request_id,omitempty"` // id of the HTTP request the row was created by
+	CreatedAt time.Time `json:"created_at"`
+	Attempt   int       `json:"attempt"` // attempts to handle the message so far
+}
+
+// NewEnvelope returns the envelope of a message tracking a chain transaction of the kind
+func NewEnvelope(kind string, entityID int, txHash string, chainID int64, requestID string) (*Envelope, error) {
+	messageType, ok := messageTypes[kind]
+	if !ok {
+		return nil, fmt.Errorf("no message type for %s transactions", kind)
+	}
+
+	return &Envelope{
+		Type:      messageType,
+		Version:   EnvelopeVersion,
+		EntityID:  entityID,
+		TxHash:    txHash,
+		ChainID:   chainID,
+		RequestID: requestID,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// Encode returns the body of the message
+func (e *Envelope) Encode() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// DecodeEnvelope parses and validates a message body of the receipt queue of the message type
+func DecodeEnvelope(body []byte, messageType string) (*Envelope, error) {
+	envelope := &Envelope{}
+
+	body = bytes.TrimSpace(body)
+	if bytes.HasPrefix(body, []byte(`"`)) {
+		// version 0, the tx hash only
+		if err := json.Unmarshal(body, &envelope.TxHash); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMessage, err)
+		}
+		envelope.Type = messageType
+	} else if err := json.Unmarshal(body, envelope); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidMessage, err)
+	}
+
+	if err := envelope.validate(messageType); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidMessage, err)
+	}
+
+	return envelope, nil
+}
+
+func (e *Envelope) validate(messageType string) error {
+	if e.Version < 0 || e.Version > EnvelopeVersion {
+		return fmt.Errorf("unsupported version %d", e.Version)
+	}
+	if e.Type != messageType {
+		return fmt.Errorf("type %q does not match the queue type %q", e.Type, messageType)
+	}
+	if !txHashExpression.MatchString(e.TxHash) {
+		return fmt.Errorf("tx hash %q is not a 32 byte hex hash", e.TxHash)
+	}
+	if e.Attempt < 0 || e.EntityID < 0 || e.ChainID < 0 {
+		return errors.New("attempt, entity id and chain id must not be negative")
+	}
+	return nil
+}
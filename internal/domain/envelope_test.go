@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+const testTxHash = "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+
+func TestEnvelope_EncodeDecode(t *testing.T) {
+	envelope, err := NewEnvelope(ChainTxKindTransfer, 7, testTxHash, 11155111, "request-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := envelope.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodeEnvelope(body, MessageTypeTransferSent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.EntityID != 7 || decoded.TxHash != testTxHash || decoded.ChainID != 11155111 ||
+		decoded.RequestID != "request-1" || decoded.Version != EnvelopeVersion {
+		t.Errorf("decoded envelope %+v does not match %+v", decoded, envelope)
+	}
+}
+
+func TestDecodeEnvelope(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "Bare tx hash of version 0", body: `"` + testTxHash + `"`},
+		{name: "Envelope", body: `{"type":"mint.sent","version":1,"tx_hash":"` + testTxHash + `","chain_id":1}`},
+		{name: "Not JSON", body: `0x12`, wantErr: true},
+		{name: "Invalid tx hash", body: `"0x12"`, wantErr: true},
+		{name: "Other message type", body: `{"type":"transfer.sent","version":1,"tx_hash":"` + testTxHash + `"}`, wantErr: true},
+		{name: "Future version", body: `{"type":"mint.sent","version":2,"tx_hash":"` + testTxHash + `"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := DecodeEnvelope([]byte(tt.body), MessageTypeMintSent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeEnvelope() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidMessage) {
+				t.Errorf("DecodeEnvelope() error = %v, want ErrInvalidMessage", err)
+			}
+			if err == nil && envelope.TxHash != testTxHash {
+				t.Errorf("DecodeEnvelope() tx hash = %s", envelope.TxHash)
+			}
+		})
+	}
+}
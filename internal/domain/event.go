@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventSchemaVersion is the version of the event format described by docs/events.schema.json.
+// Fields may be added within a version, removing or changing one requires a new version.
+const EventSchemaVersion = 1
+
+// Domain event types, they are the routing keys of the events exchange
+const (
+	EventTokenMintRequested = "token.mint_requested"
+	EventTokenMinted        = "token.minted"
+	EventTokenMintFailed    = "token.mint_failed" // the mint transaction failed or was cancelled
+	EventTransferRequested  = "transfer.requested"
+	EventTransferSucceeded  = "transfer.succeeded"
+	EventTransferFailed     = "transfer.failed" // the transfer transaction failed or was cancelled
+)
+
+// EventRepository reads the domain events for the event relay. The events are written in the same
+// database transaction as the change of the row they describe.
+type EventRepository interface {
+	ListPending(limit int) ([]*Event, error)
+	MarkPublished(id int64) error
+	MarkFailed(id int64, reason string) error
+}
+
+// Event is a domain event for other services. Data holds the token or transfer row after the change,
+// the request id is the one of the HTTP request that created the row.
+type Event struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	ChainID    int64           `json:"chain_id"`
+	RequestID  string          `json:"request_id,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+	Attempts   int             `json:"-"` // failed publications
+}
+
+// Encode returns the event as published
+func (e *Event) Encode() ([]byte, error) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestEvent_Encode(t *testing.T) {
+	event := &Event{
+		ID:         7,
+		Type:       EventTokenMinted,
+		Version:    EventSchemaVersion,
+		ChainID:    11155111,
+		OccurredAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Data:       json.RawMessage(`{"id":3,"status":"minted"}`),
+		Attempts:   2,
+	}
+
+	body, err := event.Encode()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":7,"type":"token.minted","version":1,"chain_id":11155111,
+		"occurred_at":"2024-01-01T00:00:00Z","data":{"id":3,"status":"minted"}}`, string(body))
+}
+
+// the published schema has to follow the event types and the version
+func TestEventSchema_MatchesEventTypes(t *testing.T) {
+	raw, err := os.ReadFile("../../docs/events.schema.json")
+	require.NoError(t, err)
+
+	var schema struct {
+		Properties struct {
+			Type    struct{ Enum []string }
+			Version struct{ Const int }
+		}
+	}
+	require.NoError(t, json.Unmarshal(raw, &schema))
+
+	assert.ElementsMatch(t, []string{
+		EventTokenMintRequested,
+		EventTokenMinted,
+		EventTokenMintFailed,
+		EventTransferRequested,
+		EventTransferSucceeded,
+		EventTransferFailed,
+	}, schema.Properties.Type.Enum)
+	assert.Equal(t, EventSchemaVersion, schema.Properties.Version.Const)
+}
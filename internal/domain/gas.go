@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"math/big"
+	"time"
+)
+
+// GasReportRepository aggregates the fees paid for the mints and transfers sent by the service
+type GasReportRepository interface {
+	GasReport(chainID int64, from, to time.Time) (*GasReport, error)
+}
+
+// GasUsage is the gas a mined transaction consumed and the fee paid for it
+type GasUsage struct {
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int  // wei
+	Fee               *big.Int  // wei
+	MinedAt           time.Time // time of the block the transaction was mined in
+}
+
+// NewGasUsage computes the fee of a transaction from its receipt values
+func NewGasUsage(gasUsed uint64, effectiveGasPrice *big.Int) *GasUsage {
+	if effectiveGasPrice == nil {
+		effectiveGasPrice = big.NewInt(0)
+	}
+
+	return &GasUsage{
+		GasUsed:           gasUsed,
+		EffectiveGasPrice: effectiveGasPrice,
+		Fee:               new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), effectiveGasPrice),
+	}
+}
+
+// GasReport is the spend of one chain between From and To, fees are in wei of the chain's native currency
+type GasReport struct {
+	ChainID     int64      `json:"chain_id"`
+	From        time.Time  `json:"from"`
+	To          time.Time  `json:"to"`
+	ByDay       []GasSpend `json:"by_day"`
+	ByOperation []GasSpend `json:"by_operation"`
+	ByOwner     []GasSpend `json:"by_owner"`
+}
+
+// GasSpend is the spend of one group of a gas report
+type GasSpend struct {
+	Key          string `json:"key"` // day as YYYY-MM-DD, operation (mint, transfer) or owner address
+	Transactions int    `json:"transactions"`
+	GasUsed      string `json:"gas_used"`
+	FeeWei       string `json:"fee_wei"`
+}
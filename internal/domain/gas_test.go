@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestNewGasUsage(t *testing.T) {
+	usage := NewGasUsage(21000, big.NewInt(30_000_000_000))
+	assert.Equal(t, "630000000000000", usage.Fee.String())
+
+	usage = NewGasUsage(21000, nil)
+	assert.Equal(t, "0", usage.Fee.String())
+	assert.Equal(t, "0", usage.EffectiveGasPrice.String())
+}
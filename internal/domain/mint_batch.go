@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+const (
+	MintBatchStatusProcessing = "processing"
+	MintBatchStatusCompleted  = "completed"
+
+	MintBatchItemStatusQueued = "queued"
+	MintBatchItemStatusSent   = "sent"
+	MintBatchItemStatusFailed = "failed"
+)
+
+type MintBatchRepository interface {
+	Create(batch *MintBatch) error
+	Get(id int) (*MintBatch, error)
+	MarkItemSent(itemID int, txHash string) error
+	MarkItemFailed(itemID int, reason string) error
+	UpdateStatus(id int, status string) error
+	ClaimStale(olderThan time.Time, limit int) ([]int, error)
+}
+
+// MintBatch is a set of tokens minted with contiguous nonces
+type MintBatch struct {
+	ID           int              `json:"id"`
+	CollectionID int              `json:"collection_id"`
+	Status       string           `json:"status"`
+	Total        int              `json:"total"`
+	Items        []*MintBatchItem `json:"items"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// MintBatchItem is one token of a batch. Once the item is sent, Status and TokenID follow the minted token.
+type MintBatchItem struct {
+	ID         int    `json:"id"`
+	Position   int    `json:"position"`
+	Owner      string `json:"owner"`
+	MediaUrl   string `json:"media_url"`
+	UniqueHash string `json:"unique_hash"`
+	TxHash     string `json:"tx_hash,omitempty"`
+	TokenID    string `json:"token_id,omitempty"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Token returns the token to mint for the item in the collection
+func (i *MintBatchItem) Token(collectionID int) *Token {
+	return &Token{
+		CollectionID: collectionID,
+		UniqueHash:   i.UniqueHash,
+		MediaUrl:     i.MediaUrl,
+		Owner:        i.Owner,
+	}
+}
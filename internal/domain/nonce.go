@@ -0,0 +1,6 @@
+package domain
+
+type NonceRepository interface {
+	// GetLastOutstandingNonce returns the highest nonce of the stored transactions of the address that are not mined yet
+	GetLastOutstandingNonce(chainID int64, address string) (nonce uint64, found bool, err error)
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// OutboxRepository stores the messages for the receipt queues until the relay publishes them.
+// Messages are written by ChainTransactionRepository.CreateSigned with the rows they track.
+type OutboxRepository interface {
+	ListPending(limit int) ([]*OutboxMessage, error)
+	MarkPublished(id int64) error
+	MarkFailed(id int64, reason string) error
+	MarkParked(id int64, reason string) error
+	Stats() (*OutboxStats, error)
+}
+
+// OutboxMessage is the message queued for the receipt worker of the chain transaction kind
+type OutboxMessage struct {
+	ID                 int64
+	ChainTransactionID int
+	ChainID            int64
+	Kind               string
+	Payload            []byte
+	Attempts           int
+	CreatedAt          time.Time
+}
+
+// OutboxStats describes the messages not published yet
+type OutboxStats struct {
+	Pending int
+	Lag     time.Duration // age of the oldest pending message
+	Parked  int           // messages that could not be published and are left out
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+type RoleRepository interface {
+	Grant(member *RoleMember) error
+	Revoke(collectionID int, role, account string) error
+	ListMembers(collectionID int, role string) ([]RoleMember, error)
+}
+
+// RoleMember is an account holding a role of the collection contract, reconstructed from RoleGranted and RoleRevoked events
+type RoleMember struct {
+	CollectionID int       `json:"collection_id"`
+	Role         string    `json:"role"`
+	Account      string    `json:"account"`
+	GrantedBy    string    `json:"granted_by"`
+	TxHash       string    `json:"tx_hash"`
+	BlockNumber  uint64    `json:"block_number"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -11,20 +11,43 @@ const (
 	ethereumAddressExpression = `^0x[a-fA-F0-9]{40}$`
 )
 
+const (
+	TokenStatusPending    = "pending"
+	TokenStatusConfirming = "confirming"
+	TokenStatusMinted     = "minted"
+	TokenStatusFailed     = "failed"
+	TokenStatusCancelled  = "cancelled"
+	TokenStatusUnknown    = "unknown" // the receipt could not be tracked, the message is in the dead letter queue
+)
+
 type TokenRepository interface {
-	CreateToken(token *Token) error
-	ListTokens(limit, offset int) ([]*Token, error)
+	ListTokens(collectionID int, chainID int64, limit, offset int) ([]*Token, error)
 	UpdateTokenID(tokenID, txHash string) error
+	UpdateStatus(status, txHash string) error
+	ReplaceTxHash(oldTxHash, newTxHash string) error
+	UpdateOwner(collectionID int, tokenID, owner string) error
+	MarkConfirming(txHash string, blockNumber uint64, blockHash string) (previousBlockHash string, err error)
+	RollbackToPending(txHash string) error
+	UpdateGas(txHash string, usage *GasUsage) error
 }
 
 type Token struct {
-	ID         int       `json:"id,omitempty"`
-	UniqueHash string    `json:"unique_hash,omitempty"`
-	TxHash     string    `json:"tx_hash,omitempty"`
-	MediaUrl   string    `json:"media_url" binding:"required"`
-	Owner      string    `json:"owner" binding:"required"`
-	TokenID    string    `json:"token_id,omitempty"`
-	CreatedAt  time.Time `json:"created_at,omitempty"`
+	ID           int       `json:"id,omitempty"`
+	CollectionID int       `json:"collection_id,omitempty"`
+	ChainID      int64     `json:"chain_id,omitempty"`
+	UniqueHash   string    `json:"unique_hash,omitempty"`
+	TxHash       string    `json:"tx_hash,omitempty"`
+	MediaUrl     string    `json:"media_url" binding:"required"`
+	Owner        string    `json:"owner" binding:"required"`
+	TokenID      string    `json:"token_id,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	BlockNumber  uint64    `json:"block_number,omitempty"`
+	BlockHash    string    `json:"block_hash,omitempty"`
+	GasUsed      uint64    `json:"gas_used,omitempty"`
+	GasPrice     string    `json:"effective_gas_price,omitempty"` // wei
+	FeeWei       string    `json:"fee_wei,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	RequestID    string    `json:"-"` // request that created the token, carried by its queue message
 }
 
 func (t *Token) ValidateToCreate() error {
@@ -7,21 +7,42 @@ import (
 	"time"
 )
 
+const (
+	TransferStatusPending    = "pending"
+	TransferStatusConfirming = "confirming"
+	TransferStatusSuccess    = "success"
+	TransferStatusFailed     = "failed"
+	TransferStatusCancelled  = "cancelled"
+	TransferStatusUnknown    = "unknown" // the receipt could not be tracked, the message is in the dead letter queue
+)
+
 type TransferRepository interface {
-	Create(transfer *Transfer) error
 	UpdateStatus(status, txHash string) error
-	List(limit, offset int) ([]Transfer, error)
+	ReplaceTxHash(oldTxHash, newTxHash string) error
+	CreateIfMissing(transfer *Transfer) (bool, error)
+	MarkConfirming(txHash string, blockNumber uint64, blockHash string) (previousBlockHash string, err error)
+	RollbackToPending(txHash string) error
+	UpdateGas(txHash string, usage *GasUsage) error
+	List(collectionID int, chainID int64, limit, offset int) ([]Transfer, error)
 }
 
 type Transfer struct {
-	ID          int       `json:"id"`
-	FromAddress string    `json:"from_address" binding:"required"`
-	ToAddress   string    `json:"to_address" binding:"required"`
-	TokenID     string    `json:"token_id" binding:"required"`
-	TxHash      string    `json:"tx_hash"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           int       `json:"id"`
+	CollectionID int       `json:"collection_id"`
+	ChainID      int64     `json:"chain_id"`
+	FromAddress  string    `json:"from_address" binding:"required"`
+	ToAddress    string    `json:"to_address" binding:"required"`
+	TokenID      string    `json:"token_id" binding:"required"`
+	TxHash       string    `json:"tx_hash"`
+	Status       string    `json:"status"`
+	BlockNumber  uint64    `json:"block_number,omitempty"`
+	BlockHash    string    `json:"block_hash,omitempty"`
+	GasUsed      uint64    `json:"gas_used,omitempty"`
+	GasPrice     string    `json:"effective_gas_price,omitempty"` // wei
+	FeeWei       string    `json:"fee_wei,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	RequestID    string    `json:"-"` // request that created the transfer, carried by its queue message
 }
 
 func (t *Transfer) ValidateToCreate() error {
@@ -0,0 +1,160 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"nft_service/internal/domain"
+	"strings"
+)
+
+type ApprovalRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewApprovalRepo(db *pgxpool.Pool) *ApprovalRepo {
+	return &ApprovalRepo{db: db}
+}
+
+// insertApproval stores the approval sent by the chain transaction, it is called by ChainTransactionRepo.CreateSigned
+func insertApproval(ctx context.Context, q querier, approval *domain.Approval, chainTxID int) error {
+
+	query := `INSERT INTO approvals (collection_id, kind, token_id, operator, approved, tx_hash, status,
+			  chain_transaction_id)
+			  VALUES ($1, $2, NULLIF($3, '')::NUMERIC, $4, $5, $6, $7, $8)
+			  RETURNING id, created_at, updated_at`
+
+	if approval.Status == "" {
+		approval.Status = domain.ApprovalStatusPending
+	}
+
+	err := q.QueryRow(ctx, query,
+		approval.CollectionID,
+		approval.Kind,
+		approval.TokenID,
+		approval.Operator,
+		approval.Approved,
+		approval.TxHash,
+		approval.Status,
+		chainTxID,
+	).Scan(&approval.ID, &approval.CreatedAt, &approval.UpdatedAt)
+
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate") {
+			return errors.New("approval already exists")
+		}
+		return fmt.Errorf("failed to create approval: %w", err)
+	}
+
+	return nil
+}
+
+func (a ApprovalRepo) UpdateStatus(status, txHash string) error {
+	query := `UPDATE approvals SET status = $1, updated_at = NOW() WHERE tx_hash = $2`
+	row, err := a.db.Exec(context.Background(), query, status, txHash)
+	if err != nil {
+		return fmt.Errorf("failed to update approval status: %w", err)
+	}
+
+	if row.RowsAffected() == 0 {
+		return errors.New("approval with this tx_hash does not exist")
+	}
+
+	return nil
+}
+
+// ReplaceTxHash points the approval to the transaction that replaced its transaction.
+// Nothing is updated when no approval has the old hash.
+func (a ApprovalRepo) ReplaceTxHash(oldTxHash, newTxHash string) error {
+	query := `UPDATE approvals SET tx_hash = $1, updated_at = NOW(),
+			  chain_transaction_id = (SELECT id FROM chain_transactions WHERE tx_hash = $1)
+			  WHERE tx_hash = $2`
+	if _, err := a.db.Exec(context.Background(), query, newTxHash, oldTxHash); err != nil {
+		return fmt.Errorf("failed to replace approval tx hash: %w", err)
+	}
+
+	return nil
+}
+
+// MarkConfirming records the block a pending, confirming or unknown approval was mined in and returns
+// the block hash recorded before, empty when there was none
+func (a ApprovalRepo) MarkConfirming(txHash string, blockNumber uint64, blockHash string) (string, error) {
+	var previousBlockHash string
+
+	query := `UPDATE approvals a SET status = $1, block_number = $2, block_hash = $3, updated_at = NOW()
+			  FROM (SELECT id, COALESCE(block_hash, '') AS block_hash FROM approvals WHERE tx_hash = $4) old
+			  WHERE a.id = old.id AND a.status IN ($5, $6, $1)
+			  RETURNING old.block_hash`
+
+	err := a.db.QueryRow(context.Background(), query, domain.ApprovalStatusConfirming, int64(blockNumber), blockHash,
+		txHash, domain.ApprovalStatusPending, domain.ApprovalStatusUnknown).Scan(&previousBlockHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errors.New("pending approval with this tx_hash does not exist")
+		}
+		return "", fmt.Errorf("failed to mark approval confirming: %w", err)
+	}
+
+	return previousBlockHash, nil
+}
+
+// RollbackToPending forgets the block of a confirming approval whose transaction was reorged out
+func (a ApprovalRepo) RollbackToPending(txHash string) error {
+	query := `UPDATE approvals SET status = $1, block_number = NULL, block_hash = NULL, updated_at = NOW()
+			  WHERE tx_hash = $2 AND status = $3`
+
+	if _, err := a.db.Exec(context.Background(), query, domain.ApprovalStatusPending, txHash, domain.ApprovalStatusConfirming); err != nil {
+		return fmt.Errorf("failed to rollback approval: %w", err)
+	}
+
+	return nil
+}
+
+// List lists the approvals sent for the collection
+func (a ApprovalRepo) List(collectionID, limit, offset int) ([]domain.Approval, error) {
+	var approvals []domain.Approval
+
+	query := `SELECT id, collection_id, kind, COALESCE(token_id::TEXT, ''), operator, approved, tx_hash, status,
+			  COALESCE(block_number, 0), COALESCE(block_hash, ''), created_at, updated_at
+			  FROM approvals WHERE collection_id = $1 ORDER BY id LIMIT $2 OFFSET $3`
+
+	rows, err := a.db.Query(context.Background(), query, collectionID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			approval    = domain.Approval{}
+			blockNumber int64
+		)
+		err := rows.Scan(
+			&approval.ID,
+			&approval.CollectionID,
+			&approval.Kind,
+			&approval.TokenID,
+			&approval.Operator,
+			&approval.Approved,
+			&approval.TxHash,
+			&approval.Status,
+			&blockNumber,
+			&approval.BlockHash,
+			&approval.CreatedAt,
+			&approval.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan approval row: %w", err)
+		}
+		approval.BlockNumber = uint64(blockNumber)
+		approvals = append(approvals, approval)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate approvals: %w", err)
+	}
+
+	return approvals, nil
+}
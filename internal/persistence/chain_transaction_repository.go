@@ -0,0 +1,356 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"math/big"
+	"nft_service/internal/domain"
+	"strings"
+	"time"
+)
+
+const chainTransactionColumns = `id, chain_id, tx_hash, kind, is_cancel, from_address, to_address, nonce, data, gas_limit,
+			  gas_tip_cap::TEXT, gas_fee_cap::TEXT, status, COALESCE(replaced_by, ''), speed_ups, created_at, updated_at,
+			  raw_tx, intent, broadcast_attempts`
+
+type ChainTransactionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewChainTransactionRepo(db *pgxpool.Pool) *ChainTransactionRepo {
+	return &ChainTransactionRepo{db: db}
+}
+
+// CreateSigned stores the signed transaction together with the rows of its intent and their outbox message
+// in one database transaction. The rows take the hash and the chain of the transaction. A replacement keeps
+// the intent of the transaction it replaces, whose rows are already stored.
+func (c ChainTransactionRepo) CreateSigned(tx *domain.ChainTransaction) error {
+	ctx := context.Background()
+
+	newRows := tx.Intent != nil && tx.Replaces == ""
+
+	// the intent of new rows is stored once the rows have their ids
+	var intent []byte
+	if tx.Intent != nil && !newRows {
+		data, err := json.Marshal(tx.Intent)
+		if err != nil {
+			return fmt.Errorf("failed to encode transaction intent: %w", err)
+		}
+		intent = data
+	}
+
+	dbTx, err := c.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			dbTx.Rollback(ctx)
+		}
+	}()
+
+	query := `INSERT INTO chain_transactions (chain_id, tx_hash, kind, is_cancel, from_address, to_address, nonce, data,
+			  gas_limit, gas_tip_cap, gas_fee_cap, status, speed_ups, raw_tx, intent)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			  RETURNING id, created_at, updated_at`
+
+	tx.Status = domain.ChainTxStatusSigned
+
+	err = dbTx.QueryRow(ctx, query,
+		tx.ChainID,
+		tx.TxHash,
+		tx.Kind,
+		tx.IsCancel,
+		tx.FromAddress,
+		tx.ToAddress,
+		int64(tx.Nonce),
+		tx.Data,
+		int64(tx.GasLimit),
+		numeric(tx.GasTipCap),
+		numeric(tx.GasFeeCap),
+		tx.Status,
+		tx.SpeedUps,
+		tx.RawTx,
+		intent,
+	).Scan(&tx.ID, &tx.CreatedAt, &tx.UpdatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate") {
+			return errors.New("chain transaction already exists")
+		}
+		return fmt.Errorf("failed to create chain transaction: %w", err)
+	}
+
+	if newRows {
+		_, tx.Intent.RequestID = tx.Intent.Row()
+
+		switch {
+		case tx.Intent.Token != nil:
+			tx.Intent.Token.TxHash = tx.TxHash
+			tx.Intent.Token.ChainID = tx.ChainID
+			err = insertToken(ctx, dbTx, tx.Intent.Token, tx.ID)
+		case tx.Intent.Transfer != nil:
+			tx.Intent.Transfer.TxHash = tx.TxHash
+			tx.Intent.Transfer.ChainID = tx.ChainID
+			err = insertTransfer(ctx, dbTx, tx.Intent.Transfer, tx.ID)
+		case tx.Intent.Approval != nil:
+			tx.Intent.Approval.TxHash = tx.TxHash
+			err = insertApproval(ctx, dbTx, tx.Intent.Approval, tx.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		var data []byte
+		if data, err = json.Marshal(tx.Intent); err != nil {
+			return fmt.Errorf("failed to encode transaction intent: %w", err)
+		}
+		if _, err = dbTx.Exec(ctx, `UPDATE chain_transactions SET intent = $1 WHERE id = $2`, data, tx.ID); err != nil {
+			return fmt.Errorf("failed to store transaction intent: %w", err)
+		}
+
+		// the receipt of the rows is tracked once the message is published
+		if err = insertOutbox(ctx, dbTx, tx); err != nil {
+			return err
+		}
+
+		if err = insertRequestedEvent(ctx, dbTx, tx); err != nil {
+			return err
+		}
+	}
+
+	if err = dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// MarkBroadcast records that a signed transaction was accepted by the node.
+// Transactions already marked or already mined are left as they are.
+func (c ChainTransactionRepo) MarkBroadcast(txHash string) error {
+	query := `UPDATE chain_transactions SET status = $1, updated_at = NOW() WHERE tx_hash = $2 AND status = $3`
+
+	if _, err := c.db.Exec(context.Background(), query, domain.ChainTxStatusPending, txHash,
+		domain.ChainTxStatusSigned); err != nil {
+		return fmt.Errorf("failed to mark chain transaction broadcast: %w", err)
+	}
+
+	return nil
+}
+
+// CountBroadcastAttempt counts a failed send of a signed transaction by the broadcaster and returns the failed sends
+// so far. The update time is left as it is, the transaction is still seen by the stuck transaction monitor.
+func (c ChainTransactionRepo) CountBroadcastAttempt(txHash string) (int, error) {
+	query := `UPDATE chain_transactions SET broadcast_attempts = broadcast_attempts + 1 WHERE tx_hash = $1
+			  RETURNING broadcast_attempts`
+
+	var attempts int
+	if err := c.db.QueryRow(context.Background(), query, txHash).Scan(&attempts); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errors.New("chain transaction with this tx_hash does not exist")
+		}
+		return 0, fmt.Errorf("failed to count broadcast attempt: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// Discard deletes a signed transaction the node rejected together with the rows of its intent, their outbox message
+// and their requested event
+func (c ChainTransactionRepo) Discard(txHash string) error {
+	ctx := context.Background()
+
+	dbTx, err := c.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			dbTx.Rollback(ctx)
+		}
+	}()
+
+	var id int
+	err = dbTx.QueryRow(ctx, `SELECT id FROM chain_transactions WHERE tx_hash = $1 AND status = $2 FOR UPDATE`,
+		txHash, domain.ChainTxStatusSigned).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// already broadcast or discarded
+			return nil
+		}
+		return fmt.Errorf("failed to get chain transaction: %w", err)
+	}
+
+	for _, table := range []string{"outbox", "events", "nfts", "transfers", "approvals"} {
+		if _, err = dbTx.Exec(ctx, `DELETE FROM `+table+` WHERE chain_transaction_id = $1`, id); err != nil {
+			return fmt.Errorf("failed to delete %s of chain transaction: %w", table, err)
+		}
+	}
+
+	if _, err = dbTx.Exec(ctx, `DELETE FROM chain_transactions WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete chain transaction: %w", err)
+	}
+
+	if err = dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (c ChainTransactionRepo) GetByHash(txHash string) (*domain.ChainTransaction, error) {
+	query := `SELECT ` + chainTransactionColumns + ` FROM chain_transactions WHERE tx_hash = $1`
+
+	tx, err := scanChainTransaction(c.db.QueryRow(context.Background(), query, txHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get chain transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+// ListSigned returns the transactions of the chain stored before olderThan that were never marked broadcast
+func (c ChainTransactionRepo) ListSigned(chainID int64, olderThan time.Time, limit int) ([]*domain.ChainTransaction, error) {
+	query := `SELECT ` + chainTransactionColumns + ` FROM chain_transactions
+			  WHERE chain_id = $1 AND status = $2 AND updated_at < $3
+			  ORDER BY nonce, id LIMIT $4`
+
+	return c.list(query, chainID, domain.ChainTxStatusSigned, olderThan, limit)
+}
+
+// ListStuck returns the transactions of the chain without a receipt since olderThan, including the ones
+// the broadcaster keeps failing to send
+func (c ChainTransactionRepo) ListStuck(chainID int64, olderThan time.Time, limit int) ([]*domain.ChainTransaction, error) {
+	query := `SELECT ` + chainTransactionColumns + ` FROM chain_transactions
+			  WHERE chain_id = $1 AND status IN ($2, $3) AND updated_at < $4
+			  ORDER BY id LIMIT $5`
+
+	return c.list(query, chainID, domain.ChainTxStatusPending, domain.ChainTxStatusSigned, olderThan, limit)
+}
+
+func (c ChainTransactionRepo) ListByNonce(chainID int64, fromAddress string, nonce uint64) ([]*domain.ChainTransaction, error) {
+	query := `SELECT ` + chainTransactionColumns + ` FROM chain_transactions
+			  WHERE chain_id = $1 AND from_address = $2 AND nonce = $3
+			  ORDER BY id`
+
+	return c.list(query, chainID, fromAddress, int64(nonce))
+}
+
+func (c ChainTransactionRepo) UpdateStatus(status, txHash string) error {
+	query := `UPDATE chain_transactions SET status = $1, updated_at = NOW() WHERE tx_hash = $2`
+
+	row, err := c.db.Exec(context.Background(), query, status, txHash)
+	if err != nil {
+		return fmt.Errorf("failed to update chain transaction status: %w", err)
+	}
+
+	if row.RowsAffected() == 0 {
+		return errors.New("chain transaction with this tx_hash does not exist")
+	}
+
+	return nil
+}
+
+func (c ChainTransactionRepo) MarkReplaced(txHash, replacedBy string) error {
+	query := `UPDATE chain_transactions SET status = $1, replaced_by = $2, updated_at = NOW() WHERE tx_hash = $3`
+
+	row, err := c.db.Exec(context.Background(), query, domain.ChainTxStatusReplaced, replacedBy, txHash)
+	if err != nil {
+		return fmt.Errorf("failed to mark chain transaction replaced: %w", err)
+	}
+
+	if row.RowsAffected() == 0 {
+		return errors.New("chain transaction with this tx_hash does not exist")
+	}
+
+	return nil
+}
+
+func (c ChainTransactionRepo) list(query string, args ...any) ([]*domain.ChainTransaction, error) {
+	var txs []*domain.ChainTransaction
+
+	rows, err := c.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chain transactions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tx, err := scanChainTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chain transaction row: %w", err)
+		}
+		txs = append(txs, tx)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate chain transactions: %w", err)
+	}
+
+	return txs, nil
+}
+
+func scanChainTransaction(row pgx.Row) (*domain.ChainTransaction, error) {
+	var (
+		tx                = &domain.ChainTransaction{}
+		nonce, gasLimit   int64
+		gasTipCap, feeCap string
+		intent            []byte
+	)
+
+	err := row.Scan(
+		&tx.ID,
+		&tx.ChainID,
+		&tx.TxHash,
+		&tx.Kind,
+		&tx.IsCancel,
+		&tx.FromAddress,
+		&tx.ToAddress,
+		&nonce,
+		&tx.Data,
+		&gasLimit,
+		&gasTipCap,
+		&feeCap,
+		&tx.Status,
+		&tx.ReplacedBy,
+		&tx.SpeedUps,
+		&tx.CreatedAt,
+		&tx.UpdatedAt,
+		&tx.RawTx,
+		&intent,
+		&tx.BroadcastAttempts,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if intent != nil {
+		tx.Intent = &domain.TxIntent{}
+		if err := json.Unmarshal(intent, tx.Intent); err != nil {
+			return nil, fmt.Errorf("failed to decode transaction intent: %w", err)
+		}
+	}
+
+	tx.Nonce = uint64(nonce)
+	tx.GasLimit = uint64(gasLimit)
+	tx.GasTipCap, _ = new(big.Int).SetString(gasTipCap, 10)
+	tx.GasFeeCap, _ = new(big.Int).SetString(feeCap, 10)
+
+	return tx, nil
+}
+
+// numeric converts a wei amount to a NUMERIC parameter
+func numeric(v *big.Int) pgtype.Numeric {
+	if v == nil {
+		return pgtype.Numeric{Int: big.NewInt(0), Valid: true}
+	}
+	return pgtype.Numeric{Int: v, Valid: true}
+}
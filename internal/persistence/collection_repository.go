@@ -0,0 +1,149 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"nft_service/internal/domain"
+	"strings"
+)
+
+type CollectionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewCollectionRepo(db *pgxpool.Pool) *CollectionRepo {
+	return &CollectionRepo{db: db}
+}
+
+func (r CollectionRepo) Create(collection *domain.Collection) error {
+	query := `INSERT INTO collections (address, chain_id, abi_ref, name)
+			  VALUES ($1, $2, $3, $4)
+			  RETURNING id, created_at`
+
+	err := r.db.QueryRow(context.Background(), query, collection.Address, collection.ChainID, collection.ABIRef,
+		collection.Name).Scan(&collection.ID, &collection.CreatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate") {
+			return errors.New("collection already exists")
+		}
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	return nil
+}
+
+// SeedDefault stores the env-configured collection, or refreshes its ABI reference if it is already stored.
+// The tokens, transfers, batches, approvals and role members created before collections existed are moved to it
+// together with the transfer and role indexer cursors, the rows created before chains existed are moved to its chain.
+func (r CollectionRepo) SeedDefault(collection *domain.Collection) error {
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	query := `INSERT INTO collections (address, chain_id, abi_ref, name)
+			  VALUES ($1, $2, $3, $4)
+			  ON CONFLICT (chain_id, address) DO UPDATE SET abi_ref = EXCLUDED.abi_ref
+			  RETURNING id, name, created_at`
+
+	err = tx.QueryRow(context.Background(), query, collection.Address, collection.ChainID, collection.ABIRef,
+		collection.Name).Scan(&collection.ID, &collection.Name, &collection.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to seed default collection: %w", err)
+	}
+
+	for _, table := range []string{"nfts", "transfers", "mint_batches", "approvals", "role_members"} {
+		query := `UPDATE ` + table + ` SET collection_id = $1 WHERE collection_id IS NULL`
+		if _, err = tx.Exec(context.Background(), query, collection.ID); err != nil {
+			return fmt.Errorf("failed to assign %s to default collection: %w", table, err)
+		}
+	}
+
+	// the cursors of the indexers from before collections are named without a collection id
+	for _, cursor := range []string{"transfer_events", "role_events"} {
+		query := `UPDATE indexer_cursors SET name = $1 WHERE name = $2
+				  AND NOT EXISTS (SELECT 1 FROM indexer_cursors WHERE name = $1)`
+		if _, err = tx.Exec(context.Background(), query, fmt.Sprintf("%s:%d", cursor, collection.ID), cursor); err != nil {
+			return fmt.Errorf("failed to assign %s cursor to default collection: %w", cursor, err)
+		}
+	}
+
+	for _, query := range []string{
+		`UPDATE nfts SET chain_id = $1 WHERE chain_id IS NULL`,
+		`UPDATE transfers SET chain_id = $1 WHERE chain_id IS NULL`,
+		`UPDATE chain_transactions SET chain_id = $1 WHERE chain_id = 0`,
+	} {
+		if _, err = tx.Exec(context.Background(), query, collection.ChainID); err != nil {
+			return fmt.Errorf("failed to assign rows to default chain: %w", err)
+		}
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the collection, nil if it does not exist
+func (r CollectionRepo) Get(id int) (*domain.Collection, error) {
+	collection := &domain.Collection{}
+
+	query := `SELECT id, address, chain_id, abi_ref, name, created_at FROM collections WHERE id = $1`
+	err := r.db.QueryRow(context.Background(), query, id).Scan(
+		&collection.ID,
+		&collection.Address,
+		&collection.ChainID,
+		&collection.ABIRef,
+		&collection.Name,
+		&collection.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	return collection, nil
+}
+
+// List returns the collections of the chain, all collections when chainID is 0
+func (r CollectionRepo) List(chainID int64) ([]*domain.Collection, error) {
+	var collections []*domain.Collection
+
+	query := `SELECT id, address, chain_id, abi_ref, name, created_at FROM collections
+			  WHERE $1 = 0 OR chain_id = $1 ORDER BY id`
+
+	rows, err := r.db.Query(context.Background(), query, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		collection := &domain.Collection{}
+		err := rows.Scan(
+			&collection.ID,
+			&collection.Address,
+			&collection.ChainID,
+			&collection.ABIRef,
+			&collection.Name,
+			&collection.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collection row: %w", err)
+		}
+		collections = append(collections, collection)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate collections: %w", err)
+	}
+
+	return collections, nil
+}
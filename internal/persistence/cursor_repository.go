@@ -0,0 +1,45 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CursorRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewCursorRepo(db *pgxpool.Pool) *CursorRepo {
+	return &CursorRepo{db: db}
+}
+
+func (c CursorRepo) GetCursor(name string) (uint64, bool, error) {
+	var blockNumber int64
+
+	query := `SELECT block_number FROM indexer_cursors WHERE name = $1`
+
+	err := c.db.QueryRow(context.Background(), query, name).Scan(&blockNumber)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to get cursor: %w", err)
+	}
+
+	return uint64(blockNumber), true, nil
+}
+
+func (c CursorRepo) SaveCursor(name string, blockNumber uint64) error {
+	query := `INSERT INTO indexer_cursors (name, block_number)
+			  VALUES ($1, $2)
+			  ON CONFLICT (name) DO UPDATE SET block_number = EXCLUDED.block_number, updated_at = NOW()`
+
+	if _, err := c.db.Exec(context.Background(), query, name, int64(blockNumber)); err != nil {
+		return fmt.Errorf("failed to save cursor: %w", err)
+	}
+
+	return nil
+}
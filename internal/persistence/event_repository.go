@@ -0,0 +1,131 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"nft_service/internal/domain"
+)
+
+// requestedEvents maps the events of a row to the event of its creation, which carries the request id
+var requestedEvents = map[string]string{
+	domain.EventTokenMinted:       domain.EventTokenMintRequested,
+	domain.EventTokenMintFailed:   domain.EventTokenMintRequested,
+	domain.EventTransferSucceeded: domain.EventTransferRequested,
+	domain.EventTransferFailed:    domain.EventTransferRequested,
+}
+
+type EventRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewEventRepo(db *pgxpool.Pool) *EventRepo {
+	return &EventRepo{db: db}
+}
+
+// insertEvent records the event of the row, it is called in the transaction that changes the row.
+// Without a request id the event takes the one of the event that created the row. An event with the id of
+// a chain transaction is held back while the transaction is not broadcast.
+func insertEvent(ctx context.Context, q querier, eventType string, entityID int, chainID int64, requestID string,
+	chainTransactionID int, data any,
+) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event data: %w", err)
+	}
+
+	query := `INSERT INTO events (type, entity_id, chain_id, request_id, chain_transaction_id, data)
+			  VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), (
+			      SELECT request_id FROM events WHERE entity_id = $2 AND type = $5 ORDER BY id LIMIT 1
+			  )), NULLIF($6, 0), $7)`
+
+	if _, err := q.Exec(ctx, query, eventType, entityID, chainID, requestID, requestedEvents[eventType],
+		chainTransactionID, payload); err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+
+	return nil
+}
+
+// insertRequestedEvent records the creation of the token or transfer of the intent of the chain transaction,
+// approvals have no events
+func insertRequestedEvent(ctx context.Context, q querier, tx *domain.ChainTransaction) error {
+	switch intent := tx.Intent; {
+	case intent.Token != nil:
+		token := intent.Token
+		return insertEvent(ctx, q, domain.EventTokenMintRequested, token.ID, token.ChainID, token.RequestID, tx.ID, token)
+	case intent.Transfer != nil:
+		transfer := intent.Transfer
+		return insertEvent(ctx, q, domain.EventTransferRequested, transfer.ID, transfer.ChainID, transfer.RequestID,
+			tx.ID, transfer)
+	}
+	return nil
+}
+
+// ListPending returns the oldest events not published yet. Events of transactions that are still waiting
+// for the broadcaster are held back, the node may reject the transaction and its rows are discarded.
+func (e EventRepo) ListPending(limit int) ([]*domain.Event, error) {
+	var events []*domain.Event
+
+	query := `SELECT e.id, e.type, e.chain_id, COALESCE(e.request_id, ''), e.created_at, e.data, e.attempts
+			  FROM events e LEFT JOIN chain_transactions c ON c.id = e.chain_transaction_id
+			  WHERE e.published_at IS NULL AND (c.id IS NULL OR c.status <> $1)
+			  ORDER BY e.id LIMIT $2`
+
+	rows, err := e.db.Query(context.Background(), query, domain.ChainTxStatusSigned, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event := &domain.Event{Version: domain.EventSchemaVersion}
+		err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.ChainID,
+			&event.RequestID,
+			&event.OccurredAt,
+			&event.Data,
+			&event.Attempts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event row: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (e EventRepo) MarkPublished(id int64) error {
+	query := `UPDATE events SET published_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = $1`
+
+	row, err := e.db.Exec(context.Background(), query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark event published: %w", err)
+	}
+
+	if row.RowsAffected() == 0 {
+		return errors.New("event with this id does not exist")
+	}
+
+	return nil
+}
+
+// MarkFailed counts a failed publication of the event, it stays pending
+func (e EventRepo) MarkFailed(id int64, reason string) error {
+	query := `UPDATE events SET attempts = attempts + 1, last_error = $1 WHERE id = $2`
+
+	if _, err := e.db.Exec(context.Background(), query, reason, id); err != nil {
+		return fmt.Errorf("failed to mark event failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"nft_service/internal/domain"
+	"time"
+)
+
+type GasReportRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewGasReportRepo(db *pgxpool.Pool) *GasReportRepo {
+	return &GasReportRepo{db: db}
+}
+
+// gasSpendQuery groups the final mints and transfers of the service mined in the period by the key expression.
+// The owner of a mint is the address the token was minted to, which later transfers do not change, the owner
+// of a transfer is the address the token was transferred from. A row carries the fee of the transaction that was mined for it, replaced transactions are
+// never mined and cost nothing. Approvals, role changes and nonce fills have no row and are not reported.
+const gasSpendQuery = `WITH spend AS (
+	SELECT '` + domain.ChainTxKindMint + `' AS operation, minted_to AS owner, gas_used, fee_wei, mined_at FROM nfts
+	WHERE chain_id = $1 AND fee_wei IS NOT NULL AND mined_at >= $2 AND mined_at < $3
+	UNION ALL
+	SELECT '` + domain.ChainTxKindTransfer + `', from_address, gas_used, fee_wei, mined_at FROM transfers
+	WHERE chain_id = $1 AND fee_wei IS NOT NULL AND mined_at >= $2 AND mined_at < $3
+)
+SELECT %[1]s AS key, COUNT(*), SUM(gas_used)::TEXT, SUM(fee_wei)::TEXT FROM spend GROUP BY key ORDER BY key`
+
+// GasReport aggregates the fees paid on the chain between from and to per day, per operation and per owner
+func (g GasReportRepo) GasReport(chainID int64, from, to time.Time) (*domain.GasReport, error) {
+	report := &domain.GasReport{ChainID: chainID, From: from, To: to}
+
+	for _, group := range []struct {
+		key    string
+		result *[]domain.GasSpend
+	}{
+		{key: `to_char(mined_at, 'YYYY-MM-DD')`, result: &report.ByDay},
+		{key: `operation`, result: &report.ByOperation},
+		{key: `LOWER(owner)`, result: &report.ByOwner},
+	} {
+		spend, err := g.gasSpend(fmt.Sprintf(gasSpendQuery, group.key), chainID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		*group.result = spend
+	}
+
+	return report, nil
+}
+
+func (g GasReportRepo) gasSpend(query string, chainID int64, from, to time.Time) ([]domain.GasSpend, error) {
+	spend := make([]domain.GasSpend, 0)
+
+	rows, err := g.db.Query(context.Background(), query, chainID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate gas spend: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s domain.GasSpend
+		if err := rows.Scan(&s.Key, &s.Transactions, &s.GasUsed, &s.FeeWei); err != nil {
+			return nil, fmt.Errorf("failed to scan gas spend row: %w", err)
+		}
+		spend = append(spend, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate gas spend: %w", err)
+	}
+
+	return spend, nil
+}
@@ -0,0 +1,171 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"nft_service/internal/domain"
+	"time"
+)
+
+type MintBatchRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewMintBatchRepo(db *pgxpool.Pool) *MintBatchRepo {
+	return &MintBatchRepo{db: db}
+}
+
+// Create inserts the batch with all its items in one transaction
+func (m MintBatchRepo) Create(batch *domain.MintBatch) error {
+	tx, err := m.db.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	batch.Status = domain.MintBatchStatusProcessing
+	batch.Total = len(batch.Items)
+
+	query := `INSERT INTO mint_batches (collection_id, status, total) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(context.Background(), query, batch.CollectionID, batch.Status, batch.Total).
+		Scan(&batch.ID, &batch.CreatedAt, &batch.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create mint batch: %w", err)
+	}
+
+	itemQuery := `INSERT INTO mint_batch_items (batch_id, position, owner, media_url, unique_hash, status)
+				  VALUES ($1, $2, $3, $4, $5, $6)
+				  RETURNING id`
+
+	for i, item := range batch.Items {
+		item.Position = i
+		item.Status = domain.MintBatchItemStatusQueued
+
+		err = tx.QueryRow(context.Background(), itemQuery,
+			batch.ID, item.Position, item.Owner, item.MediaUrl, item.UniqueHash, item.Status,
+		).Scan(&item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create mint batch item: %w", err)
+		}
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the batch with its items. Sent items take the tx hash, token id and status of their token,
+// so replacements and confirmations are reflected. Returns nil if the batch does not exist.
+func (m MintBatchRepo) Get(id int) (*domain.MintBatch, error) {
+	batch := &domain.MintBatch{}
+
+	query := `SELECT id, collection_id, status, total, created_at, updated_at FROM mint_batches WHERE id = $1`
+	err := m.db.QueryRow(context.Background(), query, id).
+		Scan(&batch.ID, &batch.CollectionID, &batch.Status, &batch.Total, &batch.CreatedAt, &batch.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get mint batch: %w", err)
+	}
+
+	itemsQuery := `SELECT i.id, i.position, i.owner, i.media_url, i.unique_hash,
+				   COALESCE(n.tx_hash, i.tx_hash, ''), COALESCE(n.token_id::TEXT, ''),
+				   COALESCE(n.status, i.status), COALESCE(i.error, '')
+				   FROM mint_batch_items i
+				   LEFT JOIN nfts n ON n.unique_hash = i.unique_hash
+				   WHERE i.batch_id = $1
+				   ORDER BY i.position`
+
+	rows, err := m.db.Query(context.Background(), itemsQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mint batch items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := &domain.MintBatchItem{}
+		if err := rows.Scan(
+			&item.ID,
+			&item.Position,
+			&item.Owner,
+			&item.MediaUrl,
+			&item.UniqueHash,
+			&item.TxHash,
+			&item.TokenID,
+			&item.Status,
+			&item.Error,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan mint batch item: %w", err)
+		}
+		batch.Items = append(batch.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list mint batch items: %w", err)
+	}
+
+	return batch, nil
+}
+
+func (m MintBatchRepo) MarkItemSent(itemID int, txHash string) error {
+	query := `UPDATE mint_batch_items SET status = $1, tx_hash = $2, updated_at = NOW() WHERE id = $3`
+	if _, err := m.db.Exec(context.Background(), query, domain.MintBatchItemStatusSent, txHash, itemID); err != nil {
+		return fmt.Errorf("failed to mark mint batch item sent: %w", err)
+	}
+	return nil
+}
+
+func (m MintBatchRepo) MarkItemFailed(itemID int, reason string) error {
+	query := `UPDATE mint_batch_items SET status = $1, error = $2, updated_at = NOW() WHERE id = $3`
+	if _, err := m.db.Exec(context.Background(), query, domain.MintBatchItemStatusFailed, reason, itemID); err != nil {
+		return fmt.Errorf("failed to mark mint batch item failed: %w", err)
+	}
+	return nil
+}
+
+func (m MintBatchRepo) UpdateStatus(id int, status string) error {
+	query := `UPDATE mint_batches SET status = $1, updated_at = NOW() WHERE id = $2`
+	if _, err := m.db.Exec(context.Background(), query, status, id); err != nil {
+		return fmt.Errorf("failed to update mint batch status: %w", err)
+	}
+	return nil
+}
+
+// ClaimStale takes over the processing batches without progress since olderThan, their process stopped.
+// The batches are touched, so other processes do not claim them as well.
+func (m MintBatchRepo) ClaimStale(olderThan time.Time, limit int) ([]int, error) {
+	var ids []int
+
+	query := `UPDATE mint_batches SET updated_at = NOW()
+			  WHERE id IN (
+			      SELECT id FROM mint_batches WHERE status = $1 AND updated_at < $2
+			      ORDER BY id LIMIT $3 FOR UPDATE SKIP LOCKED
+			  )
+			  RETURNING id`
+
+	rows, err := m.db.Query(context.Background(), query, domain.MintBatchStatusProcessing, olderThan, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim mint batches: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan mint batch id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim mint batches: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,134 @@
+package mocks
+
+import (
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"nft_service/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+var _ domain.MintBatchRepository = (*MockMintBatchRepository)(nil)
+
+type MockMintBatchRepository struct {
+	mock.Mock
+	batches map[int]*domain.MintBatch
+	nextID  int
+	mu      sync.RWMutex
+}
+
+func NewMockMintBatchRepository() *MockMintBatchRepository {
+	return &MockMintBatchRepository{
+		batches: make(map[int]*domain.MintBatch),
+	}
+}
+
+func (m *MockMintBatchRepository) Create(batch *domain.MintBatch) error {
+	if batch == nil {
+		return errors.New("batch cannot be nil")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	batch.ID = m.nextID
+	batch.Status = domain.MintBatchStatusProcessing
+	batch.Total = len(batch.Items)
+	batch.CreatedAt = time.Now()
+	batch.UpdatedAt = batch.CreatedAt
+
+	for i, item := range batch.Items {
+		item.ID = batch.ID*1000 + i
+		item.Position = i
+		item.Status = domain.MintBatchItemStatusQueued
+	}
+
+	m.batches[batch.ID] = copyBatch(batch)
+	return nil
+}
+
+// Get returns a copy of the batch like a read from the database, nil if it does not exist
+func (m *MockMintBatchRepository) Get(id int) (*domain.MintBatch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	batch, ok := m.batches[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyBatch(batch), nil
+}
+
+func (m *MockMintBatchRepository) MarkItemSent(itemID int, txHash string) error {
+	return m.updateItem(itemID, func(item *domain.MintBatchItem) {
+		item.Status = domain.MintBatchItemStatusSent
+		item.TxHash = txHash
+	})
+}
+
+func (m *MockMintBatchRepository) MarkItemFailed(itemID int, reason string) error {
+	return m.updateItem(itemID, func(item *domain.MintBatchItem) {
+		item.Status = domain.MintBatchItemStatusFailed
+		item.Error = reason
+	})
+}
+
+func (m *MockMintBatchRepository) UpdateStatus(id int, status string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	batch, ok := m.batches[id]
+	if !ok {
+		return errors.New("batch does not exist")
+	}
+	batch.Status = status
+	batch.UpdatedAt = time.Now()
+	return nil
+}
+
+// ClaimStale returns the processing batches without progress since olderThan and moves their progress to now
+func (m *MockMintBatchRepository) ClaimStale(olderThan time.Time, limit int) ([]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]int, 0)
+	for id, batch := range m.batches {
+		if batch.Status == domain.MintBatchStatusProcessing && batch.UpdatedAt.Before(olderThan) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	ids = ids[:min(limit, len(ids))]
+
+	for _, id := range ids {
+		m.batches[id].UpdatedAt = time.Now()
+	}
+	return ids, nil
+}
+
+func (m *MockMintBatchRepository) updateItem(itemID int, update func(item *domain.MintBatchItem)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, batch := range m.batches {
+		for _, item := range batch.Items {
+			if item.ID == itemID {
+				update(item)
+				return nil
+			}
+		}
+	}
+	return errors.New("batch item does not exist")
+}
+
+func copyBatch(batch *domain.MintBatch) *domain.MintBatch {
+	c := *batch
+	c.Items = make([]*domain.MintBatchItem, len(batch.Items))
+	for i, item := range batch.Items {
+		itemCopy := *item
+		c.Items[i] = &itemCopy
+	}
+	return &c
+}
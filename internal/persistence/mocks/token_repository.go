@@ -8,6 +8,8 @@ import (
 	"sync"
 )
 
+var _ domain.TokenRepository = (*MockTokenRepository)(nil)
+
 type MockTokenRepository struct {
 	mock.Mock
 	tokens map[int]*domain.Token
@@ -38,11 +40,23 @@ func (m *MockTokenRepository) CreateToken(token *domain.Token) error {
 		return errors.New("database error: simulated failure")
 	}
 
+	if token.Status == "" {
+		token.Status = domain.TokenStatusPending
+	}
+
 	m.tokens[token.ID] = token
 	return nil
 }
 
-func (m *MockTokenRepository) ListTokens(limit, offset int) ([]*domain.Token, error) {
+// Token returns the token with the tx hash, nil when there is none
+func (m *MockTokenRepository) Token(txHash string) *domain.Token {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.byTxHash(txHash)
+}
+
+func (m *MockTokenRepository) ListTokens(collectionID int, chainID int64, limit, offset int) ([]*domain.Token, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -52,6 +66,9 @@ func (m *MockTokenRepository) ListTokens(limit, offset int) ([]*domain.Token, er
 
 	sortedTokens := make([]*domain.Token, 0, len(m.tokens))
 	for _, token := range m.tokens {
+		if (collectionID != 0 && token.CollectionID != collectionID) || (chainID != 0 && token.ChainID != chainID) {
+			continue
+		}
 		sortedTokens = append(sortedTokens, token)
 	}
 	sort.Slice(sortedTokens, func(i, j int) bool {
@@ -69,3 +86,102 @@ func (m *MockTokenRepository) ListTokens(limit, offset int) ([]*domain.Token, er
 
 	return sortedTokens[start:end], nil
 }
+
+func (m *MockTokenRepository) UpdateTokenID(tokenID, txHash string) error {
+	return m.update(txHash, func(token *domain.Token) error {
+		token.TokenID = tokenID
+		token.Status = domain.TokenStatusMinted
+		return nil
+	})
+}
+
+func (m *MockTokenRepository) UpdateStatus(status, txHash string) error {
+	return m.update(txHash, func(token *domain.Token) error {
+		token.Status = status
+		return nil
+	})
+}
+
+func (m *MockTokenRepository) ReplaceTxHash(oldTxHash, newTxHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if token := m.byTxHash(oldTxHash); token != nil {
+		token.TxHash = newTxHash
+	}
+	return nil
+}
+
+func (m *MockTokenRepository) UpdateOwner(collectionID int, tokenID, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, token := range m.tokens {
+		if token.CollectionID == collectionID && token.TokenID == tokenID {
+			token.Owner = owner
+		}
+	}
+	return nil
+}
+
+func (m *MockTokenRepository) MarkConfirming(txHash string, blockNumber uint64, blockHash string) (string, error) {
+	var previousBlockHash string
+
+	err := m.update(txHash, func(token *domain.Token) error {
+		switch token.Status {
+		case domain.TokenStatusPending, domain.TokenStatusUnknown, domain.TokenStatusConfirming:
+		default:
+			return errors.New("pending token with this tx_hash does not exist")
+		}
+
+		previousBlockHash = token.BlockHash
+		token.Status = domain.TokenStatusConfirming
+		token.BlockNumber = blockNumber
+		token.BlockHash = blockHash
+		return nil
+	})
+
+	return previousBlockHash, err
+}
+
+func (m *MockTokenRepository) RollbackToPending(txHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if token := m.byTxHash(txHash); token != nil && token.Status == domain.TokenStatusConfirming {
+		token.Status = domain.TokenStatusPending
+		token.BlockNumber = 0
+		token.BlockHash = ""
+		token.TokenID = ""
+	}
+	return nil
+}
+
+func (m *MockTokenRepository) UpdateGas(txHash string, usage *domain.GasUsage) error {
+	return m.update(txHash, func(token *domain.Token) error {
+		token.GasUsed = usage.GasUsed
+		token.GasPrice = usage.EffectiveGasPrice.String()
+		token.FeeWei = usage.Fee.String()
+		return nil
+	})
+}
+
+func (m *MockTokenRepository) update(txHash string, fn func(token *domain.Token) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token := m.byTxHash(txHash)
+	if token == nil {
+		return errors.New("token with this tx_hash does not exist")
+	}
+	return fn(token)
+}
+
+func (m *MockTokenRepository) byTxHash(txHash string) *domain.Token {
+	for _, token := range m.tokens {
+		if token.TxHash == txHash {
+			return token
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"nft_service/internal/domain"
+)
+
+type NonceRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewNonceRepo(db *pgxpool.Pool) *NonceRepo {
+	return &NonceRepo{db: db}
+}
+
+func (n NonceRepo) GetLastOutstandingNonce(chainID int64, address string) (uint64, bool, error) {
+	var nonce sql.NullInt64
+
+	query := `SELECT MAX(nonce) FROM chain_transactions
+			  WHERE chain_id = $1 AND from_address = $2 AND status IN ($3, $4)`
+
+	err := n.db.QueryRow(context.Background(), query, chainID, address,
+		domain.ChainTxStatusSigned, domain.ChainTxStatusPending).Scan(&nonce)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get last outstanding nonce: %w", err)
+	}
+
+	return uint64(nonce.Int64), nonce.Valid, nil
+}
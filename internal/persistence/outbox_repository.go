@@ -0,0 +1,151 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"nft_service/internal/domain"
+	"time"
+)
+
+// outboxLease is how long the messages listed by a relay are hidden from the relays of other instances
+const outboxLease = time.Minute
+
+type OutboxRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepo(db *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+// insertOutbox queues the envelope of the chain transaction for the receipt worker of its kind,
+// it is called by ChainTransactionRepo.CreateSigned after the row of the intent is stored
+func insertOutbox(ctx context.Context, q querier, tx *domain.ChainTransaction) error {
+	entityID, requestID := tx.Intent.Row()
+
+	envelope, err := domain.NewEnvelope(tx.Kind, entityID, tx.TxHash, tx.ChainID, requestID)
+	if err != nil {
+		return err
+	}
+
+	payload, err := envelope.Encode()
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox message: %w", err)
+	}
+
+	query := `INSERT INTO outbox (chain_transaction_id, chain_id, kind, payload) VALUES ($1, $2, $3, $4)`
+	if _, err := q.Exec(ctx, query, tx.ID, tx.ChainID, tx.Kind, payload); err != nil {
+		return fmt.Errorf("failed to create outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// ListPending claims the oldest messages not published yet for outboxLease, so the relays of several instances
+// do not publish the same messages. Messages of transactions that are still waiting for the broadcaster are
+// held back, their receipt can not appear before. Parked messages are left out.
+func (o OutboxRepo) ListPending(limit int) ([]*domain.OutboxMessage, error) {
+	var messages []*domain.OutboxMessage
+
+	query := `WITH claimed AS (
+			      UPDATE outbox SET locked_until = NOW() + make_interval(secs => $2)
+			      WHERE id IN (
+			          SELECT o.id FROM outbox o JOIN chain_transactions c ON c.id = o.chain_transaction_id
+			          WHERE o.published_at IS NULL AND o.parked_at IS NULL AND c.status <> $1
+			            AND (o.locked_until IS NULL OR o.locked_until < NOW())
+			          ORDER BY o.id LIMIT $3
+			          FOR UPDATE OF o SKIP LOCKED
+			      )
+			      RETURNING id, chain_transaction_id, chain_id, kind, payload, attempts, created_at
+			  )
+			  SELECT * FROM claimed ORDER BY id`
+
+	rows, err := o.db.Query(context.Background(), query, domain.ChainTxStatusSigned, outboxLease.Seconds(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		message := &domain.OutboxMessage{}
+		err := rows.Scan(
+			&message.ID,
+			&message.ChainTransactionID,
+			&message.ChainID,
+			&message.Kind,
+			&message.Payload,
+			&message.Attempts,
+			&message.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox message row: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate outbox messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (o OutboxRepo) MarkPublished(id int64) error {
+	query := `UPDATE outbox SET published_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = $1`
+
+	row, err := o.db.Exec(context.Background(), query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox message published: %w", err)
+	}
+
+	if row.RowsAffected() == 0 {
+		return errors.New("outbox message with this id does not exist")
+	}
+
+	return nil
+}
+
+// MarkFailed counts a failed publication of the message, it stays pending and is released for the next round
+func (o OutboxRepo) MarkFailed(id int64, reason string) error {
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = $1, locked_until = NULL WHERE id = $2`
+
+	if _, err := o.db.Exec(context.Background(), query, reason, id); err != nil {
+		return fmt.Errorf("failed to mark outbox message failed: %w", err)
+	}
+
+	return nil
+}
+
+// MarkParked counts a failed publication of the message and leaves it out of the next rounds
+func (o OutboxRepo) MarkParked(id int64, reason string) error {
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = $1, locked_until = NULL, parked_at = NOW()
+			  WHERE id = $2`
+
+	if _, err := o.db.Exec(context.Background(), query, reason, id); err != nil {
+		return fmt.Errorf("failed to park outbox message: %w", err)
+	}
+
+	return nil
+}
+
+func (o OutboxRepo) Stats() (*domain.OutboxStats, error) {
+	var (
+		stats      = &domain.OutboxStats{}
+		lagSeconds float64
+	)
+
+	query := `SELECT COUNT(*) FILTER (WHERE parked_at IS NULL),
+			  COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(created_at) FILTER (WHERE parked_at IS NULL)), 0)::FLOAT8,
+			  COUNT(*) FILTER (WHERE parked_at IS NOT NULL)
+			  FROM outbox WHERE published_at IS NULL`
+
+	if err := o.db.QueryRow(context.Background(), query).Scan(&stats.Pending, &lagSeconds, &stats.Parked); err != nil {
+		return nil, fmt.Errorf("failed to get outbox stats: %w", err)
+	}
+
+	stats.Lag = time.Duration(lagSeconds * float64(time.Second))
+
+	return stats, nil
+}
@@ -0,0 +1,88 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"nft_service/internal/domain"
+	"strings"
+)
+
+type RoleRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRoleRepo(db *pgxpool.Pool) *RoleRepo {
+	return &RoleRepo{db: db}
+}
+
+// Grant stores the member of the collection, a repeated grant moves it to the latest event
+func (r RoleRepo) Grant(member *domain.RoleMember) error {
+	query := `INSERT INTO role_members (collection_id, role, account, granted_by, tx_hash, block_number)
+			  VALUES ($1, $2, $3, $4, $5, $6)
+			  ON CONFLICT (collection_id, role, account) DO UPDATE SET granted_by = EXCLUDED.granted_by, tx_hash = EXCLUDED.tx_hash,
+			  block_number = EXCLUDED.block_number, updated_at = NOW()`
+
+	_, err := r.db.Exec(context.Background(), query,
+		member.CollectionID,
+		strings.ToLower(member.Role),
+		strings.ToLower(member.Account),
+		strings.ToLower(member.GrantedBy),
+		member.TxHash,
+		int64(member.BlockNumber),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to grant role: %w", err)
+	}
+
+	return nil
+}
+
+func (r RoleRepo) Revoke(collectionID int, role, account string) error {
+	query := `DELETE FROM role_members WHERE collection_id = $1 AND role = $2 AND account = $3`
+	if _, err := r.db.Exec(context.Background(), query, collectionID, strings.ToLower(role),
+		strings.ToLower(account)); err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+
+	return nil
+}
+
+func (r RoleRepo) ListMembers(collectionID int, role string) ([]domain.RoleMember, error) {
+	var members []domain.RoleMember
+
+	query := `SELECT collection_id, role, account, granted_by, tx_hash, block_number, updated_at
+			  FROM role_members WHERE collection_id = $1 AND role = $2 ORDER BY block_number, account`
+
+	rows, err := r.db.Query(context.Background(), query, collectionID, strings.ToLower(role))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			member      domain.RoleMember
+			blockNumber int64
+		)
+		if err := rows.Scan(
+			&member.CollectionID,
+			&member.Role,
+			&member.Account,
+			&member.GrantedBy,
+			&member.TxHash,
+			&blockNumber,
+			&member.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan role member: %w", err)
+		}
+		member.BlockNumber = uint64(blockNumber)
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate role members: %w", err)
+	}
+
+	return members, nil
+}
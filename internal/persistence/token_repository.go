@@ -5,11 +5,19 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"nft_service/internal/domain"
 	"strings"
 )
 
+// tokenEvents are the events recorded when a token changes to the status
+var tokenEvents = map[string]string{
+	domain.TokenStatusMinted:    domain.EventTokenMinted,
+	domain.TokenStatusFailed:    domain.EventTokenMintFailed,
+	domain.TokenStatusCancelled: domain.EventTokenMintFailed,
+}
+
 type TokenRepo struct {
 	db *pgxpool.Pool
 }
@@ -18,21 +26,27 @@ func NewTokenRepo(db *pgxpool.Pool) *TokenRepo {
 	return &TokenRepo{db: db}
 }
 
-func (t TokenRepo) CreateToken(token *domain.Token) error {
+// insertToken stores the token minted by the chain transaction, it is called by ChainTransactionRepo.CreateSigned
+func insertToken(ctx context.Context, q querier, token *domain.Token, chainTxID int) error {
 
 	var tokenId sql.NullString
 
-	query := `INSERT INTO nfts (unique_hash, tx_hash, media_url, owner)
-			  VALUES ($1, $2, $3, $4)
-			  RETURNING id, unique_hash, tx_hash, media_url, owner, token_id, created_at`
+	query := `INSERT INTO nfts (collection_id, chain_id, unique_hash, tx_hash, media_url, owner, minted_to,
+			  chain_transaction_id)
+			  VALUES ($1, $2, $3, $4, $5, $6, $6, $7)
+			  RETURNING id, collection_id, chain_id, unique_hash, tx_hash, media_url, owner, token_id, status, created_at`
 
-	err := t.db.QueryRow(context.Background(), query, token.UniqueHash, token.TxHash, token.MediaUrl, token.Owner).Scan(
+	err := q.QueryRow(ctx, query, token.CollectionID, token.ChainID, token.UniqueHash, token.TxHash,
+		token.MediaUrl, token.Owner, chainTxID).Scan(
 		&token.ID,
+		&token.CollectionID,
+		&token.ChainID,
 		&token.UniqueHash,
 		&token.TxHash,
 		&token.MediaUrl,
 		&token.Owner,
 		&tokenId,
+		&token.Status,
 		&token.CreatedAt,
 	)
 
@@ -46,41 +60,148 @@ func (t TokenRepo) CreateToken(token *domain.Token) error {
 	return nil
 }
 
+// UpdateTokenID marks the token minted and records the token.minted event when it was not minted before
 func (t TokenRepo) UpdateTokenID(tokenID, txHash string) error {
-	tx, err := t.db.Begin(context.Background())
+	return t.changeStatus(txHash, domain.TokenStatusMinted, `token_id = $3`, tokenID)
+}
+
+// UpdateStatus sets the status of the token. A change to failed or cancelled records the token.mint_failed event.
+func (t TokenRepo) UpdateStatus(status, txHash string) error {
+	return t.changeStatus(txHash, status, "")
+}
+
+// changeStatus sets the status and the extra assignments of the token in one transaction with its event,
+// the event is recorded once per change of the status
+func (t TokenRepo) changeStatus(txHash, status, assignments string, args ...any) error {
+	ctx := context.Background()
+
+	tx, err := t.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer func() {
 		if err != nil {
-			tx.Rollback(context.Background())
+			tx.Rollback(ctx)
 		}
 	}()
 
-	query := `UPDATE nfts SET token_id = $1 WHERE tx_hash = $2`
-	row, err := tx.Exec(context.Background(), query, tokenID, txHash)
+	var previous string
+	err = tx.QueryRow(ctx, `SELECT status FROM nfts WHERE tx_hash = $1 FOR UPDATE`, txHash).Scan(&previous)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("token with this tx_hash does not exist")
+	}
 	if err != nil {
-		return fmt.Errorf("failed to update token id: %w", err)
+		return fmt.Errorf("failed to lock token: %w", err)
 	}
 
-	if row.RowsAffected() == 0 {
-		return errors.New("token with this tx_hash does not exist")
+	query := `UPDATE nfts SET status = $1`
+	if assignments != "" {
+		query += ", " + assignments
+	}
+	query += ` WHERE tx_hash = $2 RETURNING ` + tokenColumns
+
+	token := &domain.Token{}
+	if err = scanToken(tx.QueryRow(ctx, query, append([]any{status, txHash}, args...)...), token); err != nil {
+		return fmt.Errorf("failed to update token status: %w", err)
 	}
 
-	if err = tx.Commit(context.Background()); err != nil {
+	if eventType := tokenEvents[status]; eventType != "" && previous != status {
+		if err = insertEvent(ctx, tx, eventType, token.ID, token.ChainID, "", 0, token); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
-func (t TokenRepo) ListTokens(limit, offset int) ([]*domain.Token, error) {
+// ReplaceTxHash points the token to the transaction that replaced its mint transaction.
+// Nothing is updated when no token has the old hash.
+func (t TokenRepo) ReplaceTxHash(oldTxHash, newTxHash string) error {
+	query := `UPDATE nfts SET tx_hash = $1, chain_transaction_id = (SELECT id FROM chain_transactions WHERE tx_hash = $1)
+			  WHERE tx_hash = $2`
+	if _, err := t.db.Exec(context.Background(), query, newTxHash, oldTxHash); err != nil {
+		return fmt.Errorf("failed to replace token tx hash: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateOwner sets the current owner of a minted token of the collection.
+// Nothing is updated when the token is not known to the service.
+func (t TokenRepo) UpdateOwner(collectionID int, tokenID, owner string) error {
+	query := `UPDATE nfts SET owner = $1 WHERE collection_id = $2 AND token_id = $3`
+	if _, err := t.db.Exec(context.Background(), query, owner, collectionID, tokenID); err != nil {
+		return fmt.Errorf("failed to update token owner: %w", err)
+	}
+
+	return nil
+}
+
+// MarkConfirming records the block a pending, confirming or unknown token was mined in and returns
+// the block hash recorded before, empty when there was none
+func (t TokenRepo) MarkConfirming(txHash string, blockNumber uint64, blockHash string) (string, error) {
+	var previousBlockHash string
+
+	query := `UPDATE nfts n SET status = $1, block_number = $2, block_hash = $3
+			  FROM (SELECT id, COALESCE(block_hash, '') AS block_hash FROM nfts WHERE tx_hash = $4) old
+			  WHERE n.id = old.id AND n.status IN ($5, $6, $1)
+			  RETURNING old.block_hash`
+
+	err := t.db.QueryRow(context.Background(), query, domain.TokenStatusConfirming, int64(blockNumber), blockHash,
+		txHash, domain.TokenStatusPending, domain.TokenStatusUnknown).Scan(&previousBlockHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errors.New("pending token with this tx_hash does not exist")
+		}
+		return "", fmt.Errorf("failed to mark token confirming: %w", err)
+	}
+
+	return previousBlockHash, nil
+}
+
+// RollbackToPending forgets the block of a confirming token whose transaction was reorged out
+func (t TokenRepo) RollbackToPending(txHash string) error {
+	query := `UPDATE nfts SET status = $1, block_number = NULL, block_hash = NULL, token_id = NULL
+			  WHERE tx_hash = $2 AND status = $3`
+
+	if _, err := t.db.Exec(context.Background(), query, domain.TokenStatusPending, txHash, domain.TokenStatusConfirming); err != nil {
+		return fmt.Errorf("failed to rollback token: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateGas records the gas the final transaction of the token consumed and the fee paid for it
+func (t TokenRepo) UpdateGas(txHash string, usage *domain.GasUsage) error {
+	query := `UPDATE nfts SET gas_used = $1, effective_gas_price = $2, fee_wei = $3, mined_at = $4 WHERE tx_hash = $5`
+
+	row, err := t.db.Exec(context.Background(), query, int64(usage.GasUsed), numeric(usage.EffectiveGasPrice),
+		numeric(usage.Fee), usage.MinedAt, txHash)
+	if err != nil {
+		return fmt.Errorf("failed to update token gas: %w", err)
+	}
+
+	if row.RowsAffected() == 0 {
+		return errors.New("token with this tx_hash does not exist")
+	}
+
+	return nil
+}
+
+func (t TokenRepo) ListTokens(collectionID int, chainID int64, limit, offset int) ([]*domain.Token, error) {
 
 	var tokens []*domain.Token
 
-	query := `SELECT * FROM nfts LIMIT $1 OFFSET $2`
+	// 0 does not filter by the collection or the chain
+	query := `SELECT ` + tokenColumns + ` FROM nfts
+			  WHERE ($1::INT = 0 OR collection_id = $1) AND ($2::BIGINT = 0 OR chain_id = $2)
+			  ORDER BY id LIMIT $3 OFFSET $4`
 
-	rows, err := t.db.Query(context.TODO(), query, limit, offset)
+	rows, err := t.db.Query(context.TODO(), query, collectionID, chainID, limit, offset)
 	defer rows.Close()
 
 	if err != nil {
@@ -94,17 +215,7 @@ func (t TokenRepo) ListTokens(limit, offset int) ([]*domain.Token, error) {
 
 	for rows.Next() {
 		token := &domain.Token{}
-
-		err := rows.Scan(
-			&token.ID,
-			&token.UniqueHash,
-			&token.TxHash,
-			&token.MediaUrl,
-			&token.Owner,
-			&token.CreatedAt,
-			&token.TokenID,
-		)
-		if err != nil {
+		if err := scanToken(rows, token); err != nil {
 			return nil, errors.New("scan error " + err.Error())
 		}
 
@@ -117,3 +228,37 @@ func (t TokenRepo) ListTokens(limit, offset int) ([]*domain.Token, error) {
 
 	return tokens, err
 }
+
+// tokenColumns are the columns read by scanToken
+const tokenColumns = `id, collection_id, chain_id, unique_hash, tx_hash, media_url, owner, created_at,
+	COALESCE(token_id::TEXT, ''), status, COALESCE(block_number, 0), COALESCE(block_hash, ''), COALESCE(gas_used, 0),
+	COALESCE(effective_gas_price::TEXT, ''), COALESCE(fee_wei::TEXT, '')`
+
+func scanToken(row pgx.Row, token *domain.Token) error {
+	var blockNumber, gasUsed int64
+
+	err := row.Scan(
+		&token.ID,
+		&token.CollectionID,
+		&token.ChainID,
+		&token.UniqueHash,
+		&token.TxHash,
+		&token.MediaUrl,
+		&token.Owner,
+		&token.CreatedAt,
+		&token.TokenID,
+		&token.Status,
+		&blockNumber,
+		&token.BlockHash,
+		&gasUsed,
+		&token.GasPrice,
+		&token.FeeWei,
+	)
+	if err != nil {
+		return err
+	}
+
+	token.BlockNumber = uint64(blockNumber)
+	token.GasUsed = uint64(gasUsed)
+	return nil
+}
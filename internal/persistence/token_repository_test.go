@@ -72,26 +72,47 @@ func TestMockTokenRepository_ListTokens(t *testing.T) {
 		Owner:      "owner_3",
 	})
 
-	tokens, err := repo.ListTokens(10, 0)
+	tokens, err := repo.ListTokens(0, 0, 10, 0)
 	assert.NoError(t, err)
 	assert.Len(t, tokens, 3)
 	assert.Equal(t, "unique_hash_1", tokens[0].UniqueHash)
 	assert.Equal(t, "unique_hash_3", tokens[2].UniqueHash)
 
-	tokens, err = repo.ListTokens(2, 1)
+	tokens, err = repo.ListTokens(0, 0, 2, 1)
 	assert.NoError(t, err)
 	assert.Len(t, tokens, 2)
 	assert.Equal(t, "unique_hash_2", tokens[0].UniqueHash)
 
-	tokens, err = repo.ListTokens(10, 10)
+	tokens, err = repo.ListTokens(0, 0, 10, 10)
 	assert.NoError(t, err)
 	assert.Empty(t, tokens)
 
-	tokens, err = repo.ListTokens(-1, 0)
+	tokens, err = repo.ListTokens(0, 0, -1, 0)
 	assert.Nil(t, tokens)
 	assert.EqualError(t, err, "invalid limit or offset")
 
-	tokens, err = repo.ListTokens(10, -1)
+	tokens, err = repo.ListTokens(0, 0, 10, -1)
 	assert.Nil(t, tokens)
 	assert.EqualError(t, err, "invalid limit or offset")
 }
+
+func TestMockTokenRepository_ListTokensOfCollectionAndChain(t *testing.T) {
+	repo := mocks.NewMockTokenRepository()
+
+	repo.CreateToken(&domain.Token{ID: 1, CollectionID: 1, ChainID: 11155111, UniqueHash: "unique_hash_1"})
+	repo.CreateToken(&domain.Token{ID: 2, CollectionID: 2, ChainID: 80002, UniqueHash: "unique_hash_2"})
+	repo.CreateToken(&domain.Token{ID: 3, CollectionID: 3, ChainID: 80002, UniqueHash: "unique_hash_3"})
+
+	tokens, err := repo.ListTokens(2, 0, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+	assert.Equal(t, "unique_hash_2", tokens[0].UniqueHash)
+
+	tokens, err = repo.ListTokens(0, 80002, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+
+	tokens, err = repo.ListTokens(1, 80002, 10, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, tokens)
+}
@@ -4,11 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"nft_service/internal/domain"
 	"strings"
 )
 
+// transferEvents are the events recorded when a transfer changes to the status
+var transferEvents = map[string]string{
+	domain.TransferStatusSuccess:   domain.EventTransferSucceeded,
+	domain.TransferStatusFailed:    domain.EventTransferFailed,
+	domain.TransferStatusCancelled: domain.EventTransferFailed,
+}
+
 type TransferRepo struct {
 	db *pgxpool.Pool
 }
@@ -19,14 +27,19 @@ func NewTransferRepo(db *pgxpool.Pool) *TransferRepo {
 	}
 }
 
-func (t TransferRepo) Create(transfer *domain.Transfer) error {
+// insertTransfer stores the transfer sent by the chain transaction, it is called by ChainTransactionRepo.CreateSigned
+func insertTransfer(ctx context.Context, q querier, transfer *domain.Transfer, chainTxID int) error {
 
-	query := `INSERT INTO transfers (from_address, to_address, token_id, tx_hash, status)
-			  VALUES ($1, $2, $3, $4, $5)
-              RETURNING id, from_address, to_address, token_id, tx_hash, status, created_at, updated_at`
+	query := `INSERT INTO transfers (collection_id, chain_id, from_address, to_address, token_id, tx_hash, status,
+			  chain_transaction_id)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+              RETURNING id, collection_id, chain_id, from_address, to_address, token_id, tx_hash, status, created_at, updated_at`
 
-	err := t.db.QueryRow(context.Background(), query, transfer.FromAddress, transfer.ToAddress, transfer.TokenID, transfer.TxHash, transfer.Status).Scan(
+	err := q.QueryRow(ctx, query, transfer.CollectionID, transfer.ChainID, transfer.FromAddress,
+		transfer.ToAddress, transfer.TokenID, transfer.TxHash, transfer.Status, chainTxID).Scan(
 		&transfer.ID,
+		&transfer.CollectionID,
+		&transfer.ChainID,
 		&transfer.FromAddress,
 		&transfer.ToAddress,
 		&transfer.TokenID,
@@ -46,39 +59,150 @@ func (t TransferRepo) Create(transfer *domain.Transfer) error {
 	return nil
 }
 
+// UpdateStatus sets the status of the transfer. A change to success records the transfer.succeeded event,
+// a change to failed or cancelled the transfer.failed event.
 func (t TransferRepo) UpdateStatus(status, txHash string) error {
-	tx, err := t.db.Begin(context.Background())
+	ctx := context.Background()
+
+	tx, err := t.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer func() {
 		if err != nil {
-			tx.Rollback(context.Background())
+			tx.Rollback(ctx)
 		}
 	}()
 
-	query := `UPDATE transfers SET status = $1 WHERE tx_hash = $2`
-	row, err := tx.Exec(context.Background(), query, status, txHash)
+	var previous string
+	err = tx.QueryRow(ctx, `SELECT status FROM transfers WHERE tx_hash = $1 FOR UPDATE`, txHash).Scan(&previous)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("transfer with this tx_hash does not exist")
+	}
 	if err != nil {
+		return fmt.Errorf("failed to lock transfer: %w", err)
+	}
+
+	query := `UPDATE transfers SET status = $1, updated_at = NOW() WHERE tx_hash = $2 RETURNING ` + transferColumns
+
+	transfer := &domain.Transfer{}
+	if err = scanTransfer(tx.QueryRow(ctx, query, status, txHash), transfer); err != nil {
 		return fmt.Errorf("failed to update transfer status: %w", err)
 	}
 
-	if row.RowsAffected() == 0 {
-		return errors.New("transfer with this tx_hash does not exist")
+	// the event is recorded once per change of the status
+	if eventType := transferEvents[status]; eventType != "" && previous != status {
+		if err = insertEvent(ctx, tx, eventType, transfer.ID, transfer.ChainID, "", 0, transfer); err != nil {
+			return err
+		}
 	}
 
-	if err = tx.Commit(context.Background()); err != nil {
+	if err = tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
-func (t TransferRepo) List(limit, offset int) ([]domain.Transfer, error) {
+// CreateIfMissing inserts a transfer unless a transfer of the same token of the collection in the same transaction
+// is already stored. It reports whether a row was inserted.
+func (t TransferRepo) CreateIfMissing(transfer *domain.Transfer) (bool, error) {
+
+	query := `INSERT INTO transfers (collection_id, chain_id, from_address, to_address, token_id, tx_hash, status)
+			  SELECT $1, $2, $3, $4, $5, $6, $7
+			  WHERE NOT EXISTS (SELECT 1 FROM transfers WHERE tx_hash = $6 AND token_id = $5 AND collection_id = $1)
+			  RETURNING id, created_at, updated_at`
+
+	err := t.db.QueryRow(context.Background(), query, transfer.CollectionID, transfer.ChainID, transfer.FromAddress,
+		transfer.ToAddress, transfer.TokenID, transfer.TxHash, transfer.Status).Scan(
+		&transfer.ID,
+		&transfer.CreatedAt,
+		&transfer.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	return true, nil
+}
+
+// MarkConfirming records the block a pending, confirming or unknown transfer was mined in and returns
+// the block hash recorded before, empty when there was none
+func (t TransferRepo) MarkConfirming(txHash string, blockNumber uint64, blockHash string) (string, error) {
+	var previousBlockHash string
+
+	query := `UPDATE transfers t SET status = $1, block_number = $2, block_hash = $3, updated_at = NOW()
+			  FROM (SELECT id, COALESCE(block_hash, '') AS block_hash FROM transfers WHERE tx_hash = $4) old
+			  WHERE t.id = old.id AND t.status IN ($5, $6, $1)
+			  RETURNING old.block_hash`
+
+	err := t.db.QueryRow(context.Background(), query, domain.TransferStatusConfirming, int64(blockNumber), blockHash,
+		txHash, domain.TransferStatusPending, domain.TransferStatusUnknown).Scan(&previousBlockHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errors.New("pending transfer with this tx_hash does not exist")
+		}
+		return "", fmt.Errorf("failed to mark transfer confirming: %w", err)
+	}
+
+	return previousBlockHash, nil
+}
+
+// RollbackToPending forgets the block of a confirming transfer whose transaction was reorged out
+func (t TransferRepo) RollbackToPending(txHash string) error {
+	query := `UPDATE transfers SET status = $1, block_number = NULL, block_hash = NULL, updated_at = NOW()
+			  WHERE tx_hash = $2 AND status = $3`
+
+	if _, err := t.db.Exec(context.Background(), query, domain.TransferStatusPending, txHash, domain.TransferStatusConfirming); err != nil {
+		return fmt.Errorf("failed to rollback transfer: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceTxHash points the transfer to the transaction that replaced its transfer transaction.
+// Nothing is updated when no transfer has the old hash.
+func (t TransferRepo) ReplaceTxHash(oldTxHash, newTxHash string) error {
+	query := `UPDATE transfers SET tx_hash = $1, updated_at = NOW(),
+			  chain_transaction_id = (SELECT id FROM chain_transactions WHERE tx_hash = $1)
+			  WHERE tx_hash = $2`
+	if _, err := t.db.Exec(context.Background(), query, newTxHash, oldTxHash); err != nil {
+		return fmt.Errorf("failed to replace transfer tx hash: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateGas records the gas the final transaction of the transfer consumed and the fee paid for it
+func (t TransferRepo) UpdateGas(txHash string, usage *domain.GasUsage) error {
+	query := `UPDATE transfers SET gas_used = $1, effective_gas_price = $2, fee_wei = $3, mined_at = $4,
+			  updated_at = NOW() WHERE tx_hash = $5`
+
+	row, err := t.db.Exec(context.Background(), query, int64(usage.GasUsed), numeric(usage.EffectiveGasPrice),
+		numeric(usage.Fee), usage.MinedAt, txHash)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer gas: %w", err)
+	}
+
+	if row.RowsAffected() == 0 {
+		return errors.New("transfer with this tx_hash does not exist")
+	}
+
+	return nil
+}
+
+func (t TransferRepo) List(collectionID int, chainID int64, limit, offset int) ([]domain.Transfer, error) {
 	var transfers []domain.Transfer
 
-	query := `SELECT * FROM transfers LIMIT $1 OFFSET $2`
-	rows, err := t.db.Query(context.Background(), query, limit, offset)
+	// 0 does not filter by the collection or the chain
+	query := `SELECT ` + transferColumns + ` FROM transfers
+			  WHERE ($1::INT = 0 OR collection_id = $1) AND ($2::BIGINT = 0 OR chain_id = $2)
+			  ORDER BY id LIMIT $3 OFFSET $4`
+	rows, err := t.db.Query(context.Background(), query, collectionID, chainID, limit, offset)
 
 	defer rows.Close()
 
@@ -93,17 +217,7 @@ func (t TransferRepo) List(limit, offset int) ([]domain.Transfer, error) {
 
 	for rows.Next() {
 		transfer := domain.Transfer{}
-		err := rows.Scan(
-			&transfer.ID,
-			&transfer.FromAddress,
-			&transfer.ToAddress,
-			&transfer.TokenID,
-			&transfer.TxHash,
-			&transfer.Status,
-			&transfer.CreatedAt,
-			&transfer.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanTransfer(rows, &transfer); err != nil {
 			return nil, fmt.Errorf("failed to scan transfer row: %w", err)
 		}
 		transfers = append(transfers, transfer)
@@ -115,3 +229,37 @@ func (t TransferRepo) List(limit, offset int) ([]domain.Transfer, error) {
 
 	return transfers, nil
 }
+
+// transferColumns are the columns read by scanTransfer
+const transferColumns = `id, collection_id, chain_id, from_address, to_address, token_id, tx_hash, status, created_at,
+	updated_at, COALESCE(block_number, 0), COALESCE(block_hash, ''), COALESCE(gas_used, 0),
+	COALESCE(effective_gas_price::TEXT, ''), COALESCE(fee_wei::TEXT, '')`
+
+func scanTransfer(row pgx.Row, transfer *domain.Transfer) error {
+	var blockNumber, gasUsed int64
+
+	err := row.Scan(
+		&transfer.ID,
+		&transfer.CollectionID,
+		&transfer.ChainID,
+		&transfer.FromAddress,
+		&transfer.ToAddress,
+		&transfer.TokenID,
+		&transfer.TxHash,
+		&transfer.Status,
+		&transfer.CreatedAt,
+		&transfer.UpdatedAt,
+		&blockNumber,
+		&transfer.BlockHash,
+		&gasUsed,
+		&transfer.GasPrice,
+		&transfer.FeeWei,
+	)
+	if err != nil {
+		return err
+	}
+
+	transfer.BlockNumber = uint64(blockNumber)
+	transfer.GasUsed = uint64(gasUsed)
+	return nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+	"nft_service/internal/contract"
+	"nft_service/internal/domain"
+)
+
+// ApprovalService sends approvals from the service wallet. The approvals are stored with their transaction
+// and the outbox message for the approval status updater.
+type ApprovalService struct {
+	repo      domain.ApprovalRepository
+	contracts *contract.Registry
+}
+
+func NewApprovalService(repo domain.ApprovalRepository, contracts *contract.Registry) *ApprovalService {
+	return &ApprovalService{repo: repo, contracts: contracts}
+}
+
+// Approve approves the operator for one token of the service wallet in the collection,
+// the zero address revokes the approval
+func (s *ApprovalService) Approve(collectionID int, requestID, tokenID, operator string) (*domain.Approval, error) {
+	approval := &domain.Approval{
+		CollectionID: collectionID,
+		Kind:         domain.ApprovalKindToken,
+		TokenID:      tokenID,
+		Operator:     operator,
+		RequestID:    requestID,
+	}
+	if err := approval.ValidateToCreate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, err)
+	}
+
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return nft.Approve(approval)
+}
+
+// SetApprovalForAll grants or revokes the operator rights over all tokens of the service wallet in the collection
+func (s *ApprovalService) SetApprovalForAll(collectionID int, requestID, operator string, approved bool) (*domain.Approval, error) {
+	approval := &domain.Approval{
+		CollectionID: collectionID,
+		Kind:         domain.ApprovalKindOperator,
+		Operator:     operator,
+		Approved:     approved,
+		RequestID:    requestID,
+	}
+	if err := approval.ValidateToCreate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, err)
+	}
+
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return nft.SetApprovalForAll(approval)
+}
+
+// ListApprovals lists the approvals sent for the collection
+func (s *ApprovalService) ListApprovals(collectionID, limit, offset int) ([]domain.Approval, error) {
+	return s.repo.List(collectionID, limit, offset)
+}
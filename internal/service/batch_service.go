@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"nft_service/infrastructure/utils"
+	"nft_service/internal/contract"
+	"nft_service/internal/domain"
+	"sync"
+	"time"
+)
+
+// batchStaleAfter is how long a processing batch goes without progress before it is resumed,
+// longer than minting one chunk takes
+const batchStaleAfter = 5 * time.Minute
+
+// BatchService mints batches of tokens in the background, chunk by chunk. The processing stops between chunks
+// when ctx is cancelled, batches left processing are resumed by Start.
+type BatchService struct {
+	ctx       context.Context
+	wg        *sync.WaitGroup
+	repo      domain.MintBatchRepository
+	contracts *contract.Registry
+	maxItems  int
+	chunkSize int
+}
+
+// NewBatchService creates the service, wg is done once the batches being processed stopped
+func NewBatchService(ctx context.Context, wg *sync.WaitGroup, repo domain.MintBatchRepository,
+	contracts *contract.Registry, maxItems, chunkSize int,
+) *BatchService {
+	return &BatchService{
+		ctx:       ctx,
+		wg:        wg,
+		repo:      repo,
+		contracts: contracts,
+		maxItems:  maxItems,
+		chunkSize: chunkSize,
+	}
+}
+
+// Start resumes the batches whose processing stopped, like the ones of a previous run, every interval
+// until the context of the service is cancelled
+func (s *BatchService) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.resumeStale()
+
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			slog.Default().Info("batch resumer stopped")
+			return
+		}
+	}
+}
+
+// CreateBatch validates all tokens, stores the batch and starts minting it in the collection.
+// Nothing is stored if any token is invalid.
+func (s *BatchService) CreateBatch(collectionID int, tokens []*domain.Token) (*domain.MintBatch, error) {
+	if len(tokens) == 0 || len(tokens) > s.maxItems {
+		return nil, fmt.Errorf("%w: batch must contain between 1 and %d items", ErrInvalidArgument, s.maxItems)
+	}
+
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &domain.MintBatch{CollectionID: collectionID, Items: make([]*domain.MintBatchItem, 0, len(tokens))}
+
+	for i, token := range tokens {
+		uniqueHash, err := utils.GenerateUniqueHash()
+		if err != nil {
+			return nil, err
+		}
+		token.UniqueHash = uniqueHash
+
+		if err := token.ValidateToCreate(); err != nil {
+			return nil, fmt.Errorf("%w: item %d: %s", ErrInvalidArgument, i, err)
+		}
+
+		batch.Items = append(batch.Items, &domain.MintBatchItem{
+			Owner:      token.Owner,
+			MediaUrl:   token.MediaUrl,
+			UniqueHash: token.UniqueHash,
+		})
+	}
+
+	if err := s.repo.Create(batch); err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.process(nft, batch)
+	}()
+
+	return batch, nil
+}
+
+// GetBatch returns the batch of the collection with the progress of its items, nil if it does not exist
+func (s *BatchService) GetBatch(collectionID, id int) (*domain.MintBatch, error) {
+	batch, err := s.repo.Get(id)
+	if err != nil || batch == nil || batch.CollectionID != collectionID {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// resumeStale claims the processing batches without progress and mints their items that were not sent
+func (s *BatchService) resumeStale() {
+	l := slog.Default()
+
+	ids, err := s.repo.ClaimStale(time.Now().Add(-batchStaleAfter), 10)
+	if err != nil {
+		l.Error("failed to claim stale batches", slog.Any("error", err))
+		return
+	}
+
+	for _, id := range ids {
+		batch, err := s.repo.Get(id)
+		if err != nil || batch == nil {
+			l.Error("failed to get stale batch", slog.Int("batch_id", id), slog.Any("error", err))
+			continue
+		}
+
+		nft, err := s.contracts.Get(batch.CollectionID)
+		if err != nil {
+			l.Error("failed to get collection of stale batch", slog.Int("batch_id", id), slog.Any("error", err))
+			continue
+		}
+
+		l.Info("resuming batch", slog.Int("batch_id", id))
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.process(nft, batch)
+		}()
+	}
+}
+
+// process mints the queued items of the batch chunk by chunk. Each chunk gets contiguous nonces and one fee query,
+// the next chunk is priced only after the previous one is broadcast. Items whose token was stored are not queued
+// anymore, even when their process stopped before marking them sent.
+func (s *BatchService) process(nft contract.NFTService, batch *domain.MintBatch) {
+	l := slog.Default()
+
+	var queued []*domain.MintBatchItem
+	for _, item := range batch.Items {
+		if item.Status == domain.MintBatchItemStatusQueued {
+			queued = append(queued, item)
+		}
+	}
+
+	for start := 0; start < len(queued); start += s.chunkSize {
+		if s.ctx.Err() != nil {
+			// the batch stays processing and is resumed by the next run
+			l.Info("batch processing stopped", slog.Int("batch_id", batch.ID), slog.Int("position", queued[start].Position))
+			return
+		}
+
+		chunk := queued[start:min(start+s.chunkSize, len(queued))]
+
+		tokens := make([]*domain.Token, len(chunk))
+		for i, item := range chunk {
+			tokens[i] = item.Token(batch.CollectionID)
+			tokens[i].ChainID = nft.ChainID()
+			// the items are minted after the request returned, their messages refer to the batch
+			tokens[i].RequestID = fmt.Sprintf("batch-%d", batch.ID)
+		}
+
+		errs := nft.MintBatch(tokens)
+
+		for i, item := range chunk {
+			if errs[i] != nil {
+				l.Error("failed to mint batch item",
+					slog.Int("batch_id", batch.ID),
+					slog.Int("position", item.Position),
+					slog.Any("error", errs[i]),
+				)
+				if err := s.repo.MarkItemFailed(item.ID, errs[i].Error()); err != nil {
+					l.Error("failed to mark batch item failed", slog.Any("error", err))
+				}
+				continue
+			}
+
+			// the token is stored with its mint transaction and the outbox message for the token updater
+			if err := s.repo.MarkItemSent(item.ID, tokens[i].TxHash); err != nil {
+				l.Error("failed to mark batch item sent",
+					slog.Int("batch_id", batch.ID),
+					slog.String("tx_hash", tokens[i].TxHash),
+					slog.Any("error", err),
+				)
+			}
+		}
+
+		// the progress keeps the batch from being resumed while it is processed
+		if err := s.repo.UpdateStatus(batch.ID, domain.MintBatchStatusProcessing); err != nil {
+			l.Error("failed to update batch progress", slog.Int("batch_id", batch.ID), slog.Any("error", err))
+		}
+	}
+
+	if err := s.repo.UpdateStatus(batch.ID, domain.MintBatchStatusCompleted); err != nil {
+		l.Error("failed to complete batch", slog.Int("batch_id", batch.ID), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nft_service/internal/contract"
+	"nft_service/internal/domain"
+	"nft_service/internal/persistence/mocks"
+	"sync"
+	"testing"
+)
+
+// fakeNFT mints the batches in memory, the other methods of the contract are not used by the batches
+type fakeNFT struct {
+	contract.NFTService
+	chunks [][]string      // unique hashes of the tokens of every MintBatch call
+	fail   map[string]bool // unique hashes whose mint is rejected
+	onMint func(chunks int)
+	mu     sync.Mutex
+}
+
+func (n *fakeNFT) ChainID() int64 {
+	return 11155111
+}
+
+func (n *fakeNFT) MintBatch(tokens []*domain.Token) []error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	errs := make([]error, len(tokens))
+	hashes := make([]string, len(tokens))
+	for i, token := range tokens {
+		hashes[i] = token.UniqueHash
+		if n.fail[token.UniqueHash] {
+			errs[i] = errors.New("execution reverted")
+			continue
+		}
+		token.TxHash = fmt.Sprintf("0x%064s", token.UniqueHash)
+	}
+	n.chunks = append(n.chunks, hashes)
+
+	if n.onMint != nil {
+		n.onMint(len(n.chunks))
+	}
+	return errs
+}
+
+func (n *fakeNFT) chunkSizes() []int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	sizes := make([]int, len(n.chunks))
+	for i, chunk := range n.chunks {
+		sizes[i] = len(chunk)
+	}
+	return sizes
+}
+
+func TestBatchService_Process(t *testing.T) {
+	tests := []struct {
+		name        string
+		items       int
+		chunkSize   int
+		stopAfter   int   // chunks minted before the service is stopped, 0 runs to the end
+		failed      []int // positions whose mint is rejected
+		wantChunks  []int // sizes of the chunks minted before the stop
+		wantResumed []int // sizes of the chunks minted after resuming
+	}{
+		{
+			name:       "all chunks minted",
+			items:      5,
+			chunkSize:  2,
+			wantChunks: []int{2, 2, 1},
+		},
+		{
+			name:        "stopped between chunks resumes with the queued items",
+			items:       5,
+			chunkSize:   2,
+			stopAfter:   1,
+			wantChunks:  []int{2},
+			wantResumed: []int{2, 1},
+		},
+		{
+			name:       "stopped after the last chunk completes the batch",
+			items:      4,
+			chunkSize:  2,
+			stopAfter:  2,
+			wantChunks: []int{2, 2},
+		},
+		{
+			name:       "rejected item does not stop the batch",
+			items:      3,
+			chunkSize:  2,
+			failed:     []int{1},
+			wantChunks: []int{2, 1},
+		},
+		{
+			name:        "rejected item is not retried on resume",
+			items:       3,
+			chunkSize:   1,
+			stopAfter:   1,
+			failed:      []int{0},
+			wantChunks:  []int{1},
+			wantResumed: []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockMintBatchRepository()
+
+			batch := &domain.MintBatch{CollectionID: 1}
+			for i := 0; i < tt.items; i++ {
+				batch.Items = append(batch.Items, &domain.MintBatchItem{
+					Owner:      "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+					MediaUrl:   "https://example.com/media",
+					UniqueHash: fmt.Sprintf("hash%d", i),
+				})
+			}
+			require.NoError(t, repo.Create(batch))
+
+			failed := make(map[string]bool)
+			for _, position := range tt.failed {
+				failed[fmt.Sprintf("hash%d", position)] = true
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			nft := &fakeNFT{fail: failed, onMint: func(chunks int) {
+				if chunks == tt.stopAfter {
+					cancel()
+				}
+			}}
+
+			s := NewBatchService(ctx, &sync.WaitGroup{}, repo, nil, 100, tt.chunkSize)
+			stored, err := repo.Get(batch.ID)
+			require.NoError(t, err)
+			s.process(nft, stored)
+
+			assert.Equal(t, tt.wantChunks, nft.chunkSizes())
+
+			if tt.wantResumed != nil {
+				stopped, err := repo.Get(batch.ID)
+				require.NoError(t, err)
+				assert.Equal(t, domain.MintBatchStatusProcessing, stopped.Status)
+
+				// the next run reads the batch back like resumeStale does
+				resumed := &fakeNFT{fail: failed}
+				s = NewBatchService(context.Background(), &sync.WaitGroup{}, repo, nil, 100, tt.chunkSize)
+				s.process(resumed, stopped)
+
+				assert.Equal(t, tt.wantResumed, resumed.chunkSizes())
+			}
+
+			done, err := repo.Get(batch.ID)
+			require.NoError(t, err)
+			assert.Equal(t, domain.MintBatchStatusCompleted, done.Status)
+
+			for _, item := range done.Items {
+				if failed[item.UniqueHash] {
+					assert.Equal(t, domain.MintBatchItemStatusFailed, item.Status, item.UniqueHash)
+					continue
+				}
+				assert.Equal(t, domain.MintBatchItemStatusSent, item.Status, item.UniqueHash)
+				assert.Equal(t, fmt.Sprintf("0x%064s", item.UniqueHash), item.TxHash)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"nft_service/internal/contract"
+	"nft_service/internal/domain"
+)
+
+// ErrInvalidArgument is returned for malformed token ids, indexes, hashes and addresses
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// ChainService reads the token state directly from the contract of a collection
+type ChainService struct {
+	contracts *contract.Registry
+}
+
+func NewChainService(contracts *contract.Registry) *ChainService {
+	return &ChainService{contracts: contracts}
+}
+
+type ContractInfo struct {
+	Name   string
+	Symbol string
+}
+
+func (s *ChainService) OwnerOf(collectionID int, tokenID string) (string, error) {
+	id, err := parseUint256(tokenID)
+	if err != nil {
+		return "", err
+	}
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return "", err
+	}
+	return nft.OwnerOf(id)
+}
+
+func (s *ChainService) TokenURI(collectionID int, tokenID string) (string, error) {
+	id, err := parseUint256(tokenID)
+	if err != nil {
+		return "", err
+	}
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return "", err
+	}
+	return nft.TokenURI(id)
+}
+
+func (s *ChainService) BalanceOf(collectionID int, owner string) (*big.Int, error) {
+	if err := validateAddress(owner); err != nil {
+		return nil, err
+	}
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	return nft.BalanceOf(owner)
+}
+
+func (s *ChainService) TokenOfOwnerByIndex(collectionID int, owner, index string) (*big.Int, error) {
+	if err := validateAddress(owner); err != nil {
+		return nil, err
+	}
+	i, err := parseUint256(index)
+	if err != nil {
+		return nil, err
+	}
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	return nft.TokenOfOwnerByIndex(owner, i)
+}
+
+func (s *ChainService) TokenByIndex(collectionID int, index string) (*big.Int, error) {
+	i, err := parseUint256(index)
+	if err != nil {
+		return nil, err
+	}
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	return nft.TokenByIndex(i)
+}
+
+func (s *ChainService) HashToID(collectionID int, uniqueHash string) (*big.Int, error) {
+	if uniqueHash == "" || len(uniqueHash) > 20 {
+		return nil, fmt.Errorf("%w: invalid unique hash", ErrInvalidArgument)
+	}
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	return nft.HashToID(uniqueHash)
+}
+
+func (s *ChainService) GetApproved(collectionID int, tokenID string) (string, error) {
+	id, err := parseUint256(tokenID)
+	if err != nil {
+		return "", err
+	}
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return "", err
+	}
+	return nft.GetApproved(id)
+}
+
+func (s *ChainService) IsApprovedForAll(collectionID int, owner, operator string) (bool, error) {
+	if err := validateAddress(owner); err != nil {
+		return false, err
+	}
+	if err := validateAddress(operator); err != nil {
+		return false, err
+	}
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return false, err
+	}
+	return nft.IsApprovedForAll(owner, operator)
+}
+
+func (s *ChainService) Info(collectionID int) (*ContractInfo, error) {
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := nft.Name()
+	if err != nil {
+		return nil, err
+	}
+
+	symbol, err := nft.Symbol()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContractInfo{Name: name, Symbol: symbol}, nil
+}
+
+func validateAddress(address string) error {
+	if err := domain.ValidateAddress(address); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidArgument, err)
+	}
+	return nil
+}
+
+func parseUint256(value string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok || v.Sign() < 0 || v.BitLen() > 256 {
+		return nil, fmt.Errorf("%w: invalid number %s", ErrInvalidArgument, value)
+	}
+	return v, nil
+}
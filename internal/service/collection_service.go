@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"log/slog"
+	"nft_service/internal/contract"
+	"nft_service/internal/domain"
+)
+
+// CollectionService keeps the contract registry in sync with the stored collections
+type CollectionService struct {
+	repo       domain.CollectionRepository
+	contracts  *contract.Registry
+	minterRole string
+	wallet     string
+	onRegister func(collection *domain.Collection, nft *contract.NFTContract)
+}
+
+// NewCollectionService creates the service. onRegister is called for every collection whose contract is registered,
+// it starts the background jobs of the collection.
+func NewCollectionService(repo domain.CollectionRepository, contracts *contract.Registry, minterRole, wallet string,
+	onRegister func(collection *domain.Collection, nft *contract.NFTContract),
+) *CollectionService {
+	return &CollectionService{
+		repo:       repo,
+		contracts:  contracts,
+		minterRole: minterRole,
+		wallet:     wallet,
+		onRegister: onRegister,
+	}
+}
+
+// Load seeds the default collection and registers the contracts of all stored collections. The default collection
+// has to load and the service wallet has to be able to mint in it, other collections failing the same checks
+// are only logged.
+func (s *CollectionService) Load(defaultCollection *domain.Collection) error {
+	defaultCollection.Address = common.HexToAddress(defaultCollection.Address).Hex()
+	if err := s.repo.SeedDefault(defaultCollection); err != nil {
+		return err
+	}
+
+	collections, err := s.repo.List(0)
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range collections {
+		isDefault := collection.ID == defaultCollection.ID
+
+		nft, err := s.contracts.Build(collection)
+		if err != nil {
+			if isDefault {
+				return fmt.Errorf("failed to load default collection: %w", err)
+			}
+			slog.Default().Error("skipping collection", slog.Int("collection_id", collection.ID), slog.Any("error", err))
+			continue
+		}
+
+		if err := contract.CheckRole(nft, s.minterRole, s.wallet); err != nil {
+			if isDefault {
+				return err
+			}
+			slog.Default().Warn("service wallet cannot mint in collection",
+				slog.Int("collection_id", collection.ID),
+				slog.Any("error", err),
+			)
+		}
+
+		s.register(collection, nft)
+	}
+
+	s.contracts.SetDefault(defaultCollection.ID)
+
+	return nil
+}
+
+// Create registers a new collection. The contract has to match the generated bindings and the service wallet
+// has to be able to mint in it.
+func (s *CollectionService) Create(collection *domain.Collection) (*domain.Collection, error) {
+	if err := collection.ValidateToCreate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, err)
+	}
+	collection.Address = common.HexToAddress(collection.Address).Hex()
+
+	nft, err := s.contracts.Build(collection)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, err)
+	}
+
+	if err := contract.CheckRole(nft, s.minterRole, s.wallet); err != nil {
+		if errors.Is(err, contract.ErrMissingRole) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, err)
+		}
+		return nil, err
+	}
+
+	if err := s.repo.Create(collection); err != nil {
+		return nil, err
+	}
+
+	s.register(collection, nft)
+
+	return collection, nil
+}
+
+// List returns the collections of the chain, all collections when chainID is 0
+func (s *CollectionService) List(chainID int64) ([]*domain.Collection, error) {
+	collections, err := s.repo.List(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultID := s.contracts.DefaultID()
+	for _, collection := range collections {
+		collection.IsDefault = collection.ID == defaultID
+	}
+
+	return collections, nil
+}
+
+// Exists reports whether the contract of the collection is registered
+func (s *CollectionService) Exists(collectionID int) bool {
+	_, err := s.contracts.Get(collectionID)
+	return err == nil
+}
+
+// DefaultID returns the collection served by the routes without a collection id
+func (s *CollectionService) DefaultID() int {
+	return s.contracts.DefaultID()
+}
+
+func (s *CollectionService) register(collection *domain.Collection, nft *contract.NFTContract) {
+	s.contracts.Register(collection.ID, nft)
+	if s.onRegister != nil {
+		s.onRegister(collection, nft)
+	}
+}
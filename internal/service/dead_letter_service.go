@@ -0,0 +1,92 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"nft_service/infrastructure/messaging"
+	"nft_service/internal/domain"
+	"slices"
+)
+
+// maxDeadLetters bounds the messages inspected or replayed in one request
+const maxDeadLetters = 100
+
+// DeadLetter is a receipt message that ran out of attempts
+type DeadLetter struct {
+	Type           string `json:"type,omitempty"`
+	TxHash         string `json:"tx_hash"`
+	RequestID      string `json:"request_id,omitempty"`
+	Body           string `json:"body,omitempty"`
+	Attempts       int    `json:"attempts"`
+	LastError      string `json:"last_error"`
+	DeadLetteredAt string `json:"dead_lettered_at"`
+}
+
+// DeadLetterService inspects and replays the dead letter queues of the receipt queues
+type DeadLetterService struct {
+	mq     messaging.Queue
+	queues []string // receipt queues with a dead letter queue
+}
+
+func NewDeadLetterService(mq messaging.Queue, queues []string) *DeadLetterService {
+	return &DeadLetterService{mq: mq, queues: queues}
+}
+
+// List returns up to limit messages of the dead letter queue of the receipt queue, they stay in the queue
+func (s *DeadLetterService) List(queue string, limit int) ([]DeadLetter, error) {
+	if err := s.validate(queue, limit); err != nil {
+		return nil, err
+	}
+
+	msgs, err := s.mq.Peek(messaging.DeadLetterQueueName(queue), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters := make([]DeadLetter, 0, len(msgs))
+	for _, msg := range msgs {
+		deadLetters = append(deadLetters, newDeadLetter(msg))
+	}
+
+	return deadLetters, nil
+}
+
+// Replay moves up to limit messages of the dead letter queue back to the receipt queue with fresh attempts.
+// The rows marked unknown are tracked again once their transaction is mined.
+func (s *DeadLetterService) Replay(queue string, limit int) (int, error) {
+	if err := s.validate(queue, limit); err != nil {
+		return 0, err
+	}
+
+	return s.mq.Replay(queue, limit)
+}
+
+func (s *DeadLetterService) validate(queue string, limit int) error {
+	if !slices.Contains(s.queues, queue) {
+		return fmt.Errorf("%w: unknown queue %q", ErrInvalidArgument, queue)
+	}
+	if limit < 1 || limit > maxDeadLetters {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidArgument, maxDeadLetters)
+	}
+	return nil
+}
+
+func newDeadLetter(msg messaging.Message) DeadLetter {
+	deadLetter := DeadLetter{
+		Attempts: messaging.RetryCount(msg) + 1,
+	}
+	// messages of version 0 hold the tx hash only, invalid messages are shown as they came
+	var envelope domain.Envelope
+	if err := json.Unmarshal(msg.Body, &envelope.TxHash); err == nil {
+		deadLetter.TxHash = envelope.TxHash
+	} else if err := json.Unmarshal(msg.Body, &envelope); err == nil && envelope.TxHash != "" {
+		deadLetter.Type = envelope.Type
+		deadLetter.TxHash = envelope.TxHash
+		deadLetter.RequestID = envelope.RequestID
+	} else {
+		deadLetter.Body = string(msg.Body)
+	}
+	deadLetter.LastError, _ = msg.Headers[messaging.HeaderLastError].(string)
+	deadLetter.DeadLetteredAt, _ = msg.Headers[messaging.HeaderDeadLetteredAt].(string)
+	return deadLetter
+}
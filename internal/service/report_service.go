@@ -0,0 +1,38 @@
+package service
+
+import (
+	"fmt"
+	"nft_service/internal/domain"
+	"time"
+)
+
+// maxReportPeriod bounds the gas report, the aggregation scans every row of the period
+const maxReportPeriod = 366 * 24 * time.Hour
+
+// ReportService aggregates the spend of the service wallet
+type ReportService struct {
+	gasRepo        domain.GasReportRepository
+	defaultChainID int64
+}
+
+func NewReportService(gasRepo domain.GasReportRepository, defaultChainID int64) *ReportService {
+	return &ReportService{gasRepo: gasRepo, defaultChainID: defaultChainID}
+}
+
+// GasReport returns the fees paid on the chain for the mints and transfers mined between from and to.
+// Chain 0 is the chain of the default collection.
+func (s *ReportService) GasReport(chainID int64, from, to time.Time) (*domain.GasReport, error) {
+	if chainID == 0 {
+		chainID = s.defaultChainID
+	}
+
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidArgument)
+	}
+
+	if to.Sub(from) > maxReportPeriod {
+		return nil, fmt.Errorf("%w: period must not be longer than a year", ErrInvalidArgument)
+	}
+
+	return s.gasRepo.GasReport(chainID, from, to)
+}
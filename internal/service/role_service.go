@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"nft_service/internal/contract"
+	"nft_service/internal/domain"
+)
+
+// RoleService manages the AccessControl roles of the collection contracts
+type RoleService struct {
+	repo      domain.RoleRepository
+	contracts *contract.Registry
+}
+
+func NewRoleService(repo domain.RoleRepository, contracts *contract.Registry) *RoleService {
+	return &RoleService{repo: repo, contracts: contracts}
+}
+
+type RoleMembers struct {
+	RoleID    string              `json:"role_id"`
+	AdminRole string              `json:"admin_role"`
+	Members   []domain.RoleMember `json:"members"`
+}
+
+// Members returns the indexed members of the role in the collection and the role that administers it
+func (s *RoleService) Members(collectionID int, role string) (*RoleMembers, error) {
+	roleID, err := parseRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	adminRole, err := nft.GetRoleAdmin(roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.ListMembers(collectionID, roleID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	return &RoleMembers{RoleID: roleID.Hex(), AdminRole: adminRole.Hex(), Members: members}, nil
+}
+
+func (s *RoleService) HasRole(collectionID int, role, account string) (bool, error) {
+	roleID, nft, err := s.roleOf(collectionID, role, account)
+	if err != nil {
+		return false, err
+	}
+	return nft.HasRole(roleID, account)
+}
+
+// Grant sends grantRole to the collection contract and returns the transaction hash,
+// the member shows up once the event is final
+func (s *RoleService) Grant(collectionID int, role, account string) (string, error) {
+	roleID, nft, err := s.roleOf(collectionID, role, account)
+	if err != nil {
+		return "", err
+	}
+	return nft.GrantRole(roleID, account)
+}
+
+// Revoke sends revokeRole to the collection contract and returns the transaction hash,
+// the member is removed once the event is final
+func (s *RoleService) Revoke(collectionID int, role, account string) (string, error) {
+	roleID, nft, err := s.roleOf(collectionID, role, account)
+	if err != nil {
+		return "", err
+	}
+	return nft.RevokeRole(roleID, account)
+}
+
+// roleOf validates the role and the account and returns the role id with the collection contract
+func (s *RoleService) roleOf(collectionID int, role, account string) (common.Hash, contract.NFTService, error) {
+	roleID, err := parseRole(role)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	if err := validateAddress(account); err != nil {
+		return common.Hash{}, nil, err
+	}
+
+	nft, err := s.contracts.Get(collectionID)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	return roleID, nft, nil
+}
+
+func parseRole(role string) (common.Hash, error) {
+	roleID, err := contract.RoleID(role)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("%w: %s", ErrInvalidArgument, err)
+	}
+	return roleID, nil
+}
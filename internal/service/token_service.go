@@ -1,10 +1,7 @@
 package service
 
 import (
-	"encoding/json"
-	"github.com/rabbitmq/amqp091-go"
 	"math/big"
-	"nft_service/infrastructure/rabbit"
 	"nft_service/infrastructure/utils"
 	"nft_service/internal/contract"
 	"nft_service/internal/domain"
@@ -12,57 +9,54 @@ import (
 
 type TokenService struct {
 	repo      domain.TokenRepository
-	contract  contract.NFTService
-	mq        *rabbit.RabbitMQ
-	queueName amqp091.Queue
+	contracts *contract.Registry
 }
 
-func NewTokenService(repo domain.TokenRepository, contract contract.NFTService, mq *rabbit.RabbitMQ, queueName amqp091.Queue) *TokenService {
-	return &TokenService{repo: repo, contract: contract, mq: mq, queueName: queueName}
+func NewTokenService(repo domain.TokenRepository, contracts *contract.Registry) *TokenService {
+	return &TokenService{repo: repo, contracts: contracts}
 }
 
-func (t *TokenService) CreateToken(token *domain.Token) (*domain.Token, error) {
+func (t *TokenService) CreateToken(collectionID int, token *domain.Token) (*domain.Token, error) {
 
-	var err error
-
-	token.UniqueHash, err = utils.GenerateUniqueHash()
-	if err != nil {
-		return nil, err
-	}
-
-	if err := token.ValidateToCreate(); err != nil {
-		return nil, err
-	}
-
-	token, err = t.contract.Mint(token)
+	nft, err := t.contracts.Get(collectionID)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := t.repo.CreateToken(token); err != nil {
-		return nil, err
-	}
+	token.CollectionID = collectionID
+	token.ChainID = nft.ChainID()
 
-	queueBody, err := json.Marshal(token.TxHash)
+	token.UniqueHash, err = utils.GenerateUniqueHash()
 	if err != nil {
 		return nil, err
 	}
 
-	if err := t.mq.Publish(t.queueName.Name, queueBody); err != nil {
+	if err := token.ValidateToCreate(); err != nil {
 		return nil, err
 	}
 
-	return token, nil
+	// the token is stored with its mint transaction and the outbox message for the token updater
+	return nft.Mint(token)
 }
 
-func (t *TokenService) ListTokens(limit, offset int) ([]*domain.Token, error) {
-	return t.repo.ListTokens(limit, offset)
+// ListTokens lists the tokens of the collection, of every collection when collectionID is 0,
+// on the chain when chainID is not 0
+func (t *TokenService) ListTokens(collectionID int, chainID int64, limit, offset int) ([]*domain.Token, error) {
+	return t.repo.ListTokens(collectionID, chainID, limit, offset)
 }
 
-func (t *TokenService) TotalSupply() (*big.Int, error) {
-	return t.contract.TotalSupply()
+func (t *TokenService) TotalSupply(collectionID int) (*big.Int, error) {
+	nft, err := t.contracts.Get(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	return nft.TotalSupply()
 }
 
-func (t *TokenService) ExactTotalSupply() (*big.Int, error) {
-	return t.contract.ExactTotalSupply()
+func (t *TokenService) ExactTotalSupply(collectionID int) (*big.Int, error) {
+	nft, err := t.contracts.Get(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	return nft.ExactTotalSupply()
 }
@@ -1,49 +1,34 @@
 package service
 
 import (
-	"encoding/json"
-	"github.com/rabbitmq/amqp091-go"
-	"nft_service/infrastructure/rabbit"
 	"nft_service/internal/contract"
 	"nft_service/internal/domain"
 )
 
 type TransferService struct {
 	repo      domain.TransferRepository
-	contract  contract.NFTService
-	mq        *rabbit.RabbitMQ
-	queueName amqp091.Queue
+	contracts *contract.Registry
 }
 
-func NewTransferService(repo domain.TransferRepository, contract contract.NFTService, mq *rabbit.RabbitMQ, queueName amqp091.Queue) *TransferService {
-	return &TransferService{repo: repo, contract: contract, mq: mq, queueName: queueName}
+func NewTransferService(repo domain.TransferRepository, contracts *contract.Registry) *TransferService {
+	return &TransferService{repo: repo, contracts: contracts}
 }
 
-func (s *TransferService) CreateTransfer(transfer *domain.Transfer) (*domain.Transfer, error) {
-	var err error
-
-	transfer, err = s.contract.TransferToken(transfer)
-	if err != nil {
-		return nil, err
-	}
-
-	err = s.repo.Create(transfer)
+func (s *TransferService) CreateTransfer(collectionID int, transfer *domain.Transfer) (*domain.Transfer, error) {
+	nft, err := s.contracts.Get(collectionID)
 	if err != nil {
 		return nil, err
 	}
 
-	queueBody, err := json.Marshal(transfer.TxHash)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := s.mq.Publish(s.queueName.Name, queueBody); err != nil {
-		return nil, err
-	}
+	transfer.CollectionID = collectionID
+	transfer.ChainID = nft.ChainID()
 
-	return transfer, nil
+	// the transfer is stored with its transaction and the outbox message for the transfer status updater
+	return nft.TransferToken(transfer)
 }
 
-func (s *TransferService) ListTransfer(limit, offset int) ([]domain.Transfer, error) {
-	return s.repo.List(limit, offset)
+// ListTransfer lists the transfers of the collection, of every collection when collectionID is 0,
+// on the chain when chainID is not 0
+func (s *TransferService) ListTransfer(collectionID int, chainID int64, limit, offset int) ([]domain.Transfer, error) {
+	return s.repo.List(collectionID, chainID, limit, offset)
 }
@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"nft_service/internal/domain"
+)
+
+func (w *Worker) ApprovalStatusUpdater() error {
+	return w.consumeReceipts(receiptRows{
+		name:           "approval",
+		queue:          w.approvalQueue,
+		messageType:    domain.MessageTypeApprovalSent,
+		markConfirming: w.approvalRepo.MarkConfirming,
+		rollback:       w.approvalRepo.RollbackToPending,
+		markUnknown: func(txHash string) error {
+			return w.approvalRepo.UpdateStatus(domain.ApprovalStatusUnknown, txHash)
+		},
+		settle: w.settleApproval,
+	})
+}
+
+func (w *Worker) settleApproval(txHash string, receipt *types.Receipt, chainTx *domain.ChainTransaction) error {
+	var txStatus string
+	switch {
+	case chainTx != nil && chainTx.IsCancel:
+		txStatus = domain.ApprovalStatusCancelled
+	case receipt.Status == types.ReceiptStatusSuccessful:
+		txStatus = domain.ApprovalStatusSuccess
+	default:
+		txStatus = domain.ApprovalStatusFailed
+	}
+
+	return w.approvalRepo.UpdateStatus(txStatus, txHash)
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"log/slog"
+	"nft_service/internal/contract"
+	"nft_service/internal/domain"
+	"strings"
+	"time"
+)
+
+// broadcastGrace is how long a signed transaction is left to the request that stored it,
+// longer than the timeout of the requests sending transactions
+const broadcastGrace = time.Minute
+
+// maxBroadcastAttempts is how many failed sends of a transaction the broadcaster retries before it cancels it
+const maxBroadcastAttempts = 10
+
+// Broadcaster periodically sends the stored transactions that were never marked broadcast,
+// because the node rejected them or the service stopped between storing and sending them
+func (w *Worker) Broadcaster(ctx context.Context, interval time.Duration) {
+	l := slog.Default()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.broadcastSigned()
+		case <-ctx.Done():
+			l.Info("broadcaster stopped")
+			return
+		}
+	}
+}
+
+func (w *Worker) broadcastSigned() {
+	l := slog.Default()
+
+	signed, err := w.chainTxRepo.ListSigned(w.chainID, time.Now().Add(-broadcastGrace), 100)
+	if err != nil {
+		l.Error("failed to list signed transactions", slog.Any("error", err))
+		return
+	}
+
+	for _, tx := range signed {
+		if err := w.broadcast(tx); err != nil {
+			l.Error("failed to broadcast signed transaction",
+				slog.String("tx_hash", tx.TxHash),
+				slog.Uint64("nonce", tx.Nonce),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+func (w *Worker) broadcast(tx *domain.ChainTransaction) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(tx.RawTx); err != nil {
+		return fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+
+	sendErr := w.client.SendTransaction(ctx, signedTx)
+	if sendErr == nil || strings.Contains(strings.ToLower(sendErr.Error()), "already known") {
+		slog.Default().Info("signed transaction broadcast", slog.String("tx_hash", tx.TxHash))
+		return w.chainTxRepo.MarkBroadcast(tx.TxHash)
+	}
+
+	// the transaction may have been sent before the service stopped and be mined already
+	_, err := w.client.TransactionReceipt(ctx, common.HexToHash(tx.TxHash))
+	if err == nil {
+		return w.chainTxRepo.MarkBroadcast(tx.TxHash)
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return fmt.Errorf("failed to get receipt: %w", err)
+	}
+
+	confirmedNonce, err := w.client.NonceAt(ctx, common.HexToAddress(tx.FromAddress), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get confirmed nonce: %w", err)
+	}
+
+	if confirmedNonce > tx.Nonce {
+		return w.resolveUsedNonce(ctx, tx)
+	}
+
+	// a no-op could not be paid either, the balance monitor alerts and the transaction is sent once the wallet is topped up
+	if contract.IsInsufficientFundsError(sendErr) {
+		return fmt.Errorf("failed to send transaction: %w", sendErr)
+	}
+
+	attempts, err := w.chainTxRepo.CountBroadcastAttempt(tx.TxHash)
+	if err != nil {
+		return err
+	}
+
+	// a cancel has nothing to give up to, it is retried until its nonce is taken
+	if tx.IsCancel || (!contract.IsPermanentSendError(sendErr) && attempts < maxBroadcastAttempts) {
+		return fmt.Errorf("failed to send transaction: %w", sendErr)
+	}
+
+	// the nonce is filled with a cancel, so the transactions after it can be mined
+	slog.Default().Warn("cancelling transaction the node does not accept",
+		slog.String("tx_hash", tx.TxHash),
+		slog.Int("attempts", attempts),
+		slog.Any("error", sendErr),
+	)
+	replacement, err := w.replacer.Cancel(tx)
+	if err != nil {
+		return err
+	}
+
+	return w.trackReplacement(tx.TxHash, replacement)
+}
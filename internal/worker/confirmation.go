@@ -0,0 +1,178 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/core/types"
+	"log/slog"
+	"nft_service/infrastructure/messaging"
+	"nft_service/internal/domain"
+	"time"
+)
+
+// errReorged is returned when the block of a receipt is no longer part of the canonical chain
+var errReorged = errors.New("receipt block is not canonical")
+
+// retryLater moves the message to the retry queue of its attempt, so the transaction is checked again after
+// a growing delay. After the last attempt the message goes to the dead letter queue of the work queue
+// and markUnknown is called with the tx hash.
+func (w *Worker) retryLater(msg messaging.Message, envelope *domain.Envelope, queue, reason string,
+	markUnknown func(txHash string) error,
+) {
+	var (
+		l       = slog.Default()
+		retries = messaging.RetryCount(msg)
+	)
+
+	// messages of version 0 are upgraded to the envelope on their first retry
+	envelope.Version = domain.EnvelopeVersion
+	envelope.Attempt = retries + 1
+	body, err := envelope.Encode()
+	if err != nil {
+		w.deadLetter(msg, queue, err.Error())
+		return
+	}
+
+	if retries+1 >= w.retry.MaxAttempts {
+		if !w.moveToDeadLetters(msg, queue, body, retries, reason) {
+			return
+		}
+
+		if err := markUnknown(envelope.TxHash); err != nil {
+			l.Error("failed to mark row unknown", slog.String("tx_hash", envelope.TxHash), slog.Any("error", err))
+		}
+
+		l.Warn("message moved to dead letter queue",
+			slog.String("queue", queue),
+			slog.String("tx_hash", envelope.TxHash),
+			slog.String("request_id", envelope.RequestID),
+			slog.Int("attempts", retries+1),
+			slog.String("reason", reason),
+		)
+		msg.Ack()
+		return
+	}
+
+	delay := w.retry.Delay(retries)
+	headers := messaging.Headers{
+		messaging.HeaderRetryCount: int32(retries + 1),
+		messaging.HeaderLastError:  reason,
+	}
+	if err := w.mq.Publish(messaging.RetryQueueName(queue, delay), body, headers); err != nil {
+		w.requeue(msg, err)
+		return
+	}
+
+	msg.Ack()
+}
+
+// deadLetter moves a message that can never be handled to the dead letter queue without retries
+func (w *Worker) deadLetter(msg messaging.Message, queue, reason string) {
+	if !w.moveToDeadLetters(msg, queue, msg.Body, messaging.RetryCount(msg), reason) {
+		return
+	}
+
+	slog.Default().Warn("invalid message moved to dead letter queue",
+		slog.String("queue", queue),
+		slog.String("body", string(msg.Body)),
+		slog.String("reason", reason),
+	)
+	msg.Ack()
+}
+
+// moveToDeadLetters publishes the body to the dead letter queue of the work queue, the message is requeued
+// when that fails
+func (w *Worker) moveToDeadLetters(msg messaging.Message, queue string, body []byte, retries int, reason string) bool {
+	headers := messaging.Headers{
+		messaging.HeaderRetryCount:     int32(retries),
+		messaging.HeaderLastError:      reason,
+		messaging.HeaderDeadLetteredAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := w.mq.Publish(messaging.DeadLetterQueueName(queue), body, headers); err != nil {
+		w.requeue(msg, err)
+		return false
+	}
+	return true
+}
+
+// requeue returns the message to its work queue when it can not be moved to another queue
+func (w *Worker) requeue(msg messaging.Message, err error) {
+	slog.Default().Error("failed to move message, requeueing it", slog.Any("error", err))
+	time.Sleep(w.retry.BaseDelay)
+	msg.Nack(true)
+}
+
+// isFinal reports whether the receipt is at or below the safe head and its block is still canonical
+func (w *Worker) isFinal(ctx context.Context, receipt *types.Receipt) (bool, error) {
+	safeHead, err := w.client.SafeHead(ctx, w.finality)
+	if err != nil {
+		return false, err
+	}
+
+	if receipt.BlockNumber.Uint64() > safeHead {
+		return false, nil
+	}
+
+	header, err := w.client.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return false, fmt.Errorf("failed to get receipt block header: %w", err)
+	}
+
+	if header.Hash() != receipt.BlockHash {
+		return false, errReorged
+	}
+
+	return true, nil
+}
+
+// confirm records the receipt block through markConfirming and reports whether the transaction is final.
+// A receipt that moved to a different block since the last check restarts the confirmation,
+// a receipt whose block is not canonical any more rolls the row back through rollback.
+func (w *Worker) confirm(ctx context.Context, txHash string, receipt *types.Receipt,
+	markConfirming func(txHash string, blockNumber uint64, blockHash string) (string, error),
+	rollback func(txHash string) error,
+) (bool, error) {
+	l := slog.Default()
+
+	previousBlockHash, err := markConfirming(txHash, receipt.BlockNumber.Uint64(), receipt.BlockHash.Hex())
+	if err != nil {
+		return false, err
+	}
+
+	if previousBlockHash != "" && previousBlockHash != receipt.BlockHash.Hex() {
+		l.Warn("transaction moved to a different block, confirmation restarted",
+			slog.String("tx_hash", txHash),
+			slog.String("previous_block_hash", previousBlockHash),
+			slog.String("block_hash", receipt.BlockHash.Hex()),
+		)
+		return false, nil
+	}
+
+	final, err := w.isFinal(ctx, receipt)
+	if errors.Is(err, errReorged) {
+		l.Warn("transaction block reorged out, rolling back",
+			slog.String("tx_hash", txHash),
+			slog.String("block_hash", receipt.BlockHash.Hex()),
+		)
+		return false, rollback(txHash)
+	}
+
+	return final, err
+}
+
+// gasUsage returns the gas the final receipt consumed with the time of its block
+func (w *Worker) gasUsage(receipt *types.Receipt) (*domain.GasUsage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	header, err := w.client.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block of receipt: %w", err)
+	}
+
+	usage := domain.NewGasUsage(receipt.GasUsed, receipt.EffectiveGasPrice)
+	usage.MinedAt = time.Unix(int64(header.Time), 0).UTC()
+
+	return usage, nil
+}
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log/slog"
+	"nft_service/infrastructure/messaging"
+	"nft_service/internal/domain"
+	"time"
+)
+
+var (
+	eventsPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "events_published_total",
+		Help: "Domain events published to the events exchange by type.",
+	}, []string{"type"})
+
+	eventPublishFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "events_publish_failures_total",
+		Help: "Failed publications of domain events.",
+	})
+)
+
+// EventRelay publishes the domain events to the topic exchange with their type as routing key.
+// An event is published at least once and in the order it was recorded, subscribers skip the ids they have seen.
+// The requested event of a transaction that is not broadcast yet is held back and follows the later events.
+type EventRelay struct {
+	repo      domain.EventRepository
+	publisher messaging.TopicPublisher
+	exchange  string
+}
+
+func NewEventRelay(repo domain.EventRepository, publisher messaging.TopicPublisher, exchange string) *EventRelay {
+	return &EventRelay{repo: repo, publisher: publisher, exchange: exchange}
+}
+
+// Start publishes the pending events every interval until the context is cancelled
+func (r *EventRelay) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.relay()
+		case <-ctx.Done():
+			slog.Default().Info("event relay stopped")
+			return
+		}
+	}
+}
+
+// relay publishes the pending events in order. The round stops at the first failure,
+// the remaining events are retried with it on the next tick.
+func (r *EventRelay) relay() {
+	l := slog.Default()
+
+	events, err := r.repo.ListPending(100)
+	if err != nil {
+		l.Error("failed to list events", slog.Any("error", err))
+		return
+	}
+
+	for _, event := range events {
+		if err := r.publish(event); err != nil {
+			eventPublishFailures.Inc()
+
+			l.Warn("failed to publish event",
+				slog.Int64("id", event.ID),
+				slog.String("type", event.Type),
+				slog.Int("attempts", event.Attempts+1),
+				slog.Any("error", err),
+			)
+			if err := r.repo.MarkFailed(event.ID, err.Error()); err != nil {
+				l.Error("failed to mark event failed", slog.Any("error", err))
+			}
+			return
+		}
+
+		if err := r.repo.MarkPublished(event.ID); err != nil {
+			// the event is published again on the next tick
+			l.Error("failed to mark event published", slog.Int64("id", event.ID), slog.Any("error", err))
+			return
+		}
+
+		eventsPublished.WithLabelValues(event.Type).Inc()
+	}
+}
+
+func (r *EventRelay) publish(event *domain.Event) error {
+	body, err := event.Encode()
+	if err != nil {
+		return err
+	}
+
+	return r.publisher.PublishTopic(r.exchange, event.Type, body)
+}
@@ -0,0 +1,147 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"log/slog"
+	"nft_service/infrastructure/messaging"
+	"nft_service/internal/domain"
+	"strconv"
+	"time"
+)
+
+var (
+	outboxPending = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "outbox_pending_messages",
+		Help: "Number of outbox messages not published to the queues yet.",
+	})
+
+	outboxLag = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "outbox_lag_seconds",
+		Help: "Age of the oldest outbox message not published to the queues yet.",
+	})
+
+	outboxPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "outbox_published_total",
+		Help: "Outbox messages published to the queues by chain and kind.",
+	}, []string{"chain_id", "kind"})
+
+	outboxPublishFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "outbox_publish_failures_total",
+		Help: "Failed publications of outbox messages.",
+	})
+
+	outboxParked = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "outbox_parked_messages",
+		Help: "Number of outbox messages left out after they could not be published.",
+	})
+)
+
+// maxOutboxAttempts is how many failed publications of a message the relay retries before it parks it
+const maxOutboxAttempts = 20
+
+// errNoReceiptQueue is returned for messages of a chain or kind the service has no receipt queue for
+var errNoReceiptQueue = errors.New("no receipt queue")
+
+// OutboxRelay publishes the outbox messages to the receipt queues of their chain and kind.
+// A message is published at least once, the receipt workers tolerate duplicates.
+type OutboxRelay struct {
+	repo   domain.OutboxRepository
+	mq     messaging.Queue
+	queues map[string]map[int64]string // receipt queue by chain transaction kind and chain
+}
+
+func NewOutboxRelay(repo domain.OutboxRepository, mq messaging.Queue, queues map[string]map[int64]string) *OutboxRelay {
+	return &OutboxRelay{repo: repo, mq: mq, queues: queues}
+}
+
+// Start publishes the pending messages every interval until the context is cancelled
+func (r *OutboxRelay) Start(ctx context.Context, interval time.Duration) {
+	l := slog.Default()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.relay()
+			r.observe()
+		case <-ctx.Done():
+			l.Info("outbox relay stopped")
+			return
+		}
+	}
+}
+
+// relay publishes the pending messages in order. A failed message is retried on the next tick and parked after
+// maxOutboxAttempts, or at once when there is no queue for it. The round stops when the backend is unavailable.
+func (r *OutboxRelay) relay() {
+	l := slog.Default()
+
+	messages, err := r.repo.ListPending(100)
+	if err != nil {
+		l.Error("failed to list outbox messages", slog.Any("error", err))
+		return
+	}
+
+	for _, message := range messages {
+		if err := r.publish(message); err != nil {
+			outboxPublishFailures.Inc()
+
+			// a message the broker did not accept may have been stored anyway, publishing it again
+			// may duplicate it, which the receipt workers tolerate
+			l.Warn("failed to publish outbox message",
+				slog.Int64("id", message.ID),
+				slog.Int("attempts", message.Attempts+1),
+				slog.Any("error", err),
+			)
+
+			if errors.Is(err, errNoReceiptQueue) || errors.Is(err, messaging.ErrUnknownQueue) ||
+				message.Attempts+1 >= maxOutboxAttempts {
+				l.Error("outbox message parked", slog.Int64("id", message.ID), slog.Any("error", err))
+				if err := r.repo.MarkParked(message.ID, err.Error()); err != nil {
+					l.Error("failed to park outbox message", slog.Any("error", err))
+				}
+			} else if err := r.repo.MarkFailed(message.ID, err.Error()); err != nil {
+				l.Error("failed to mark outbox message failed", slog.Any("error", err))
+			}
+
+			if !r.mq.Connected() {
+				return
+			}
+			continue
+		}
+
+		if err := r.repo.MarkPublished(message.ID); err != nil {
+			// the message is published again on the next tick
+			l.Error("failed to mark outbox message published", slog.Int64("id", message.ID), slog.Any("error", err))
+			return
+		}
+
+		outboxPublished.WithLabelValues(strconv.FormatInt(message.ChainID, 10), message.Kind).Inc()
+	}
+}
+
+func (r *OutboxRelay) publish(message *domain.OutboxMessage) error {
+	queue, ok := r.queues[message.Kind][message.ChainID]
+	if !ok {
+		return fmt.Errorf("%w for %s transactions of chain %d", errNoReceiptQueue, message.Kind, message.ChainID)
+	}
+
+	return r.mq.Publish(queue, message.Payload, nil)
+}
+
+func (r *OutboxRelay) observe() {
+	stats, err := r.repo.Stats()
+	if err != nil {
+		slog.Default().Error("failed to get outbox stats", slog.Any("error", err))
+		return
+	}
+
+	outboxPending.Set(float64(stats.Pending))
+	outboxLag.Set(stats.Lag.Seconds())
+	outboxParked.Set(float64(stats.Parked))
+}
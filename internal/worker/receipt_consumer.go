@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"log/slog"
+	"nft_service/infrastructure/messaging"
+	"nft_service/internal/domain"
+	"time"
+)
+
+// receiptRows describes the rows a receipt queue tracks and how their status follows the receipt
+type receiptRows struct {
+	name           string // row name in logs
+	queue          string
+	messageType    string
+	markConfirming func(txHash string, blockNumber uint64, blockHash string) (string, error)
+	rollback       func(txHash string) error
+	markUnknown    func(txHash string) error
+	// settle records the final receipt of the transaction, chainTx is its stored record, nil when it is not known
+	settle func(txHash string, receipt *types.Receipt, chainTx *domain.ChainTransaction) error
+}
+
+// consumeReceipts handles the messages of the receipt queue until it is closed: the row follows the receipt
+// of its transaction until it is final, a message is retried with backoff while there is no final receipt
+func (w *Worker) consumeReceipts(rows receiptRows) error {
+	msgs, err := w.mq.Consume(rows.queue)
+	if err != nil {
+		return fmt.Errorf("failed to consume %s update message", rows.name)
+	}
+
+	maxWorkers := 10
+	semaphore := make(chan struct{}, maxWorkers)
+
+	for msg := range msgs {
+		semaphore <- struct{}{}
+
+		go func(msg messaging.Message) {
+			defer func() { <-semaphore }()
+			w.handleReceipt(rows, msg)
+		}(msg)
+	}
+
+	return nil
+}
+
+func (w *Worker) handleReceipt(rows receiptRows, msg messaging.Message) {
+	l := slog.Default()
+
+	envelope, err := domain.DecodeEnvelope(msg.Body, rows.messageType)
+	if err != nil {
+		w.deadLetter(msg, rows.queue, err.Error())
+		return
+	}
+	txHash := envelope.TxHash
+
+	retry := func(reason string) {
+		w.retryLater(msg, envelope, rows.queue, reason, rows.markUnknown)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Second)
+	defer cancel()
+
+	receipt, err := w.client.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if err != nil {
+		if w.isSuperseded(txHash) {
+			msg.Ack()
+			return
+		}
+		if errors.Is(err, ethereum.NotFound) {
+			// the receipt disappeared if the row was confirming
+			if err := rows.rollback(txHash); err != nil {
+				l.Error("failed to rollback "+rows.name, slog.Any("error", err))
+			}
+		}
+		retry(err.Error())
+		return
+	}
+
+	final, err := w.confirm(ctx, txHash, receipt, rows.markConfirming, rows.rollback)
+	if err != nil {
+		l.Error("failed to confirm "+rows.name+" transaction", slog.String("tx_hash", txHash), slog.Any("error", err))
+	}
+	if !final {
+		retry("transaction is not final")
+		return
+	}
+
+	if err := rows.settle(txHash, receipt, w.markMined(txHash)); err != nil {
+		l.Error("failed to update "+rows.name, slog.String("tx_hash", txHash), slog.Any("error", err))
+		retry(err.Error())
+		return
+	}
+
+	if err := msg.Ack(); err != nil {
+		l.Error("failed to ack message", slog.Any("error", err))
+	}
+}
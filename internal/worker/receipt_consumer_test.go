@@ -0,0 +1,210 @@
+package worker
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nft_service/infrastructure/messaging"
+	"nft_service/internal/domain"
+	"nft_service/internal/persistence/mocks"
+	"testing"
+	"time"
+)
+
+// newMintMessage stores a pending token with its transaction and returns the message of the token queue
+func newMintMessage(t *testing.T, w *Worker, txHash common.Hash) []byte {
+	t.Helper()
+
+	tokens := w.tokenRepo.(*mocks.MockTokenRepository)
+	require.NoError(t, tokens.CreateToken(&domain.Token{ID: 1, TxHash: txHash.Hex(), UniqueHash: "hash"}))
+
+	chainTxs := w.chainTxRepo.(*fakeChainTxRepo)
+	chainTxs.txs[txHash.Hex()] = &domain.ChainTransaction{
+		Kind:      domain.ChainTxKindMint,
+		TxHash:    txHash.Hex(),
+		ToAddress: testCollection.Hex(),
+		Status:    domain.ChainTxStatusPending,
+	}
+
+	envelope, err := domain.NewEnvelope(domain.ChainTxKindMint, 1, txHash.Hex(), w.chainID, "request-1")
+	require.NoError(t, err)
+	body, err := envelope.Encode()
+	require.NoError(t, err)
+
+	return body
+}
+
+func TestHandleReceipt_Confirmation(t *testing.T) {
+	tests := []struct {
+		name      string
+		prepare   func(t *testing.T, eth *fakeEthService, tokens *mocks.MockTokenRepository, txHash common.Hash)
+		status    string
+		blockHash func(eth *fakeEthService) string
+		retried   bool
+	}{
+		{
+			name: "final receipt settles the token",
+			prepare: func(t *testing.T, eth *fakeEthService, _ *mocks.MockTokenRepository, txHash common.Hash) {
+				eth.mine(90, txHash, transferLog(testCollection, common.Address{}, testOwner, 7))
+			},
+			status:    domain.TokenStatusMinted,
+			blockHash: func(eth *fakeEthService) string { return eth.headers[90].Hash().Hex() },
+		},
+		{
+			name: "receipt above the safe head keeps the token confirming",
+			prepare: func(t *testing.T, eth *fakeEthService, _ *mocks.MockTokenRepository, txHash common.Hash) {
+				eth.mine(99, txHash, transferLog(testCollection, common.Address{}, testOwner, 7))
+			},
+			status:    domain.TokenStatusConfirming,
+			blockHash: func(eth *fakeEthService) string { return eth.headers[99].Hash().Hex() },
+			retried:   true,
+		},
+		{
+			name: "reorged block rolls the token back",
+			prepare: func(t *testing.T, eth *fakeEthService, _ *mocks.MockTokenRepository, txHash common.Hash) {
+				eth.mine(90, txHash, transferLog(testCollection, common.Address{}, testOwner, 7))
+				eth.reorg(90)
+			},
+			status:    domain.TokenStatusPending,
+			blockHash: func(*fakeEthService) string { return "" },
+			retried:   true,
+		},
+		{
+			name: "receipt moved to another block restarts the confirmation",
+			prepare: func(t *testing.T, eth *fakeEthService, tokens *mocks.MockTokenRepository, txHash common.Hash) {
+				_, err := tokens.MarkConfirming(txHash.Hex(), 89, common.HexToHash("0x89").Hex())
+				require.NoError(t, err)
+				eth.mine(90, txHash, transferLog(testCollection, common.Address{}, testOwner, 7))
+			},
+			status:    domain.TokenStatusConfirming,
+			blockHash: func(eth *fakeEthService) string { return eth.headers[90].Hash().Hex() },
+			retried:   true,
+		},
+		{
+			name: "disappeared receipt rolls the token back",
+			prepare: func(t *testing.T, _ *fakeEthService, tokens *mocks.MockTokenRepository, txHash common.Hash) {
+				_, err := tokens.MarkConfirming(txHash.Hex(), 90, common.HexToHash("0x90").Hex())
+				require.NoError(t, err)
+			},
+			status:    domain.TokenStatusPending,
+			blockHash: func(*fakeEthService) string { return "" },
+			retried:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eth := newFakeEthService(100)
+			w := newTestWorker(t, eth)
+			tokens := w.tokenRepo.(*mocks.MockTokenRepository)
+
+			txHash := common.HexToHash("0xabc")
+			body := newMintMessage(t, w, txHash)
+			tt.prepare(t, eth, tokens, txHash)
+
+			acked := false
+			msg := messaging.NewMessage(body, nil, func() error { acked = true; return nil }, nil)
+			w.handleReceipt(w.tokenRows(), msg)
+
+			assert.True(t, acked)
+
+			token := tokens.Token(txHash.Hex())
+			assert.Equal(t, tt.status, token.Status)
+			assert.Equal(t, tt.blockHash(eth), token.BlockHash)
+
+			// a retried message comes back to the queue after the delay of its attempt
+			if tt.retried {
+				require.Eventually(t, func() bool {
+					msgs, err := w.mq.Peek(w.tokenQueue, 10)
+					return err == nil && len(msgs) == 1
+				}, time.Second, 5*time.Millisecond)
+
+				msgs, err := w.mq.Peek(w.tokenQueue, 10)
+				require.NoError(t, err)
+				assert.Equal(t, 1, messaging.RetryCount(msgs[0]))
+			} else {
+				time.Sleep(2 * w.retry.MaxDelay)
+				msgs, err := w.mq.Peek(w.tokenQueue, 10)
+				require.NoError(t, err)
+				assert.Empty(t, msgs)
+			}
+		})
+	}
+}
+
+func TestHandleReceipt_RetryThenDeadLetter(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        []byte                                                     // message body, the mint message when nil
+		before      func(attempt int, eth *fakeEthService, txHash common.Hash) // called before every attempt
+		attempts    int
+		status      string
+		deadLetters int
+	}{
+		{
+			name:        "receipt that never appears is dead lettered after the last attempt",
+			attempts:    3,
+			status:      domain.TokenStatusUnknown,
+			deadLetters: 1,
+		},
+		{
+			name: "receipt final on the second attempt",
+			before: func(attempt int, eth *fakeEthService, txHash common.Hash) {
+				if attempt == 2 {
+					eth.mine(90, txHash, transferLog(testCollection, common.Address{}, testOwner, 7))
+				}
+			},
+			attempts: 2,
+			status:   domain.TokenStatusMinted,
+		},
+		{
+			name:        "invalid message is dead lettered without retries",
+			body:        []byte("not a message"),
+			attempts:    1,
+			status:      domain.TokenStatusPending,
+			deadLetters: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eth := newFakeEthService(100)
+			w := newTestWorker(t, eth)
+			tokens := w.tokenRepo.(*mocks.MockTokenRepository)
+
+			txHash := common.HexToHash("0xabc")
+			body := newMintMessage(t, w, txHash)
+			if tt.body != nil {
+				body = tt.body
+			}
+			require.NoError(t, w.mq.Publish(w.tokenQueue, body, nil))
+
+			msgs, err := w.mq.Consume(w.tokenQueue)
+			require.NoError(t, err)
+
+			for attempt := 1; attempt <= tt.attempts; attempt++ {
+				select {
+				case msg := <-msgs:
+					if tt.before != nil {
+						tt.before(attempt, eth, txHash)
+					}
+					w.handleReceipt(w.tokenRows(), msg)
+				case <-time.After(time.Second):
+					t.Fatalf("attempt %d was not delivered", attempt)
+				}
+			}
+
+			select {
+			case <-msgs:
+				t.Fatalf("message delivered after %d attempts", tt.attempts)
+			case <-time.After(4 * w.retry.MaxDelay):
+			}
+
+			assert.Equal(t, tt.status, tokens.Token(txHash.Hex()).Status)
+
+			deadLetters, err := w.mq.Peek(messaging.DeadLetterQueueName(w.tokenQueue), 10)
+			require.NoError(t, err)
+			assert.Len(t, deadLetters, tt.deadLetters)
+		})
+	}
+}
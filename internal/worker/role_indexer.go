@@ -0,0 +1,127 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"log/slog"
+	"nft_service/internal/contract"
+	"nft_service/internal/domain"
+	"time"
+)
+
+const roleIndexerCursor = "role_events"
+
+// RoleSeed is checked with hasRole when the role events are first indexed from the current head,
+// the grants made before it, like the ones of the deployment, are not among the indexed events
+type RoleSeed struct {
+	Contract contract.NFTService
+	Roles    []string
+	Accounts []string
+}
+
+// RoleIndexer follows the final RoleGranted and RoleRevoked events of the collection contract
+// from the stored cursor and keeps role_members in sync. Role changes are rare, so the events are only polled.
+func (w *Worker) RoleIndexer(ctx context.Context, cfg IndexerConfig, seed RoleSeed) {
+	var (
+		l      = slog.Default().With(slog.Int("collection_id", cfg.CollectionID))
+		cursor = fmt.Sprintf("%s:%d", roleIndexerCursor, cfg.CollectionID)
+	)
+
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	topics := []common.Hash{contract.RoleGrantedTopic, contract.RoleRevokedTopic}
+
+	for {
+		// the events are only indexed once the members are seeded, the cursor skips the seeding afterwards
+		if err := w.seedRoles(ctx, cfg, cursor, seed); err != nil {
+			l.Error("failed to seed role members", slog.Any("error", err))
+		} else if err := w.syncEvents(ctx, cfg, cursor, topics, func(log types.Log) error {
+			return w.applyRoleLog(cfg.CollectionID, log)
+		}); err != nil {
+			l.Error("failed to index role events", slog.Any("error", err))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			l.Info("role indexer stopped")
+			return
+		}
+	}
+}
+
+// seedRoles stores the accounts of the seed holding its roles when there is neither a cursor nor a start block
+func (w *Worker) seedRoles(ctx context.Context, cfg IndexerConfig, cursor string, seed RoleSeed) error {
+	if cfg.StartBlock != nil {
+		return nil
+	}
+
+	_, found, err := w.cursorRepo.GetCursor(cursor)
+	if err != nil || found {
+		return err
+	}
+
+	head, err := w.client.SafeHead(ctx, w.finality)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range seed.Roles {
+		id, err := contract.RoleID(role)
+		if err != nil {
+			return err
+		}
+
+		for _, account := range seed.Accounts {
+			hasRole, err := seed.Contract.HasRole(id, account)
+			if err != nil {
+				return fmt.Errorf("failed to check role %s: %w", role, err)
+			}
+			if !hasRole {
+				continue
+			}
+
+			member := &domain.RoleMember{CollectionID: cfg.CollectionID, Role: id.Hex(), Account: account, BlockNumber: head}
+			if err := w.roleRepo.Grant(member); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyRoleLog adds the account of a RoleGranted event to the role members of the collection
+// and removes it on RoleRevoked
+func (w *Worker) applyRoleLog(collectionID int, log types.Log) error {
+	if log.Removed || len(log.Topics) == 0 {
+		return nil
+	}
+
+	switch log.Topics[0] {
+	case contract.RoleGrantedTopic:
+		event, err := w.nft.ParseRoleGranted(log)
+		if err != nil {
+			return err
+		}
+		return w.roleRepo.Grant(&domain.RoleMember{
+			CollectionID: collectionID,
+			Role:         common.Hash(event.Role).Hex(),
+			Account:      event.Account.Hex(),
+			GrantedBy:    event.Sender.Hex(),
+			TxHash:       log.TxHash.Hex(),
+			BlockNumber:  log.BlockNumber,
+		})
+	case contract.RoleRevokedTopic:
+		event, err := w.nft.ParseRoleRevoked(log)
+		if err != nil {
+			return err
+		}
+		return w.roleRepo.Revoke(collectionID, common.Hash(event.Role).Hex(), event.Account.Hex())
+	}
+
+	return nil
+}
@@ -1,52 +1,75 @@
 package worker
 
 import (
+	"context"
 	"fmt"
-	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/rabbitmq/amqp091-go"
-	"nft_service/infrastructure/rabbit"
+	"github.com/ethereum/go-ethereum/common"
+	"nft_service/infrastructure/blockchain"
+	"nft_service/infrastructure/messaging"
+	"nft_service/internal/contract"
+	"nft_service/internal/contract/bindings"
 	"nft_service/internal/domain"
-	"strings"
+	"time"
 )
 
 type WorkerUpdater interface {
 	TokenUpdater() error
 	TransferStatusUpdater() error
+	ApprovalStatusUpdater() error
+	StuckTxMonitor(ctx context.Context, interval, stuckAfter time.Duration, maxSpeedUps int)
+	Broadcaster(ctx context.Context, interval time.Duration)
+	TransferIndexer(ctx context.Context, cfg IndexerConfig)
+	RoleIndexer(ctx context.Context, cfg IndexerConfig, seed RoleSeed)
 }
 
+// Worker follows the transactions and events of one chain
 type Worker struct {
-	client        *ethclient.Client
-	mq            *rabbit.RabbitMQ
-	tokenQueue    amqp091.Queue
-	transferQueue amqp091.Queue
+	chainID       int64
+	client        *blockchain.Client
+	mq            messaging.Queue
+	tokenQueue    string
+	transferQueue string
+	approvalQueue string
 	tokenRepo     domain.TokenRepository
 	transferRepo  domain.TransferRepository
-	contractABI   string
-	parsedABI     *abi.ABI
+	approvalRepo  domain.ApprovalRepository
+	chainTxRepo   domain.ChainTransactionRepository
+	cursorRepo    domain.CursorRepository
+	roleRepo      domain.RoleRepository
+	finality      blockchain.Finality
+	retry         messaging.RetryPolicy // backoff of the receipt queues
+	replacer      contract.TxReplacer
+	nft           *bindings.NFTFilterer
 }
 
-func NewWorker(url string, mq *rabbit.RabbitMQ, tokenQueue amqp091.Queue,
-	transferQueue amqp091.Queue, tokenRepo domain.TokenRepository, transferRepo domain.TransferRepository,
-	contractABI string,
+func NewWorker(chainID int64, client *blockchain.Client, mq messaging.Queue, tokenQueue, transferQueue,
+	approvalQueue string, tokenRepo domain.TokenRepository,
+	transferRepo domain.TransferRepository, approvalRepo domain.ApprovalRepository,
+	chainTxRepo domain.ChainTransactionRepository, cursorRepo domain.CursorRepository, roleRepo domain.RoleRepository,
+	replacer contract.TxReplacer, finality blockchain.Finality, retry messaging.RetryPolicy,
 ) (*Worker, error) {
-	client, err := ethclient.Dial(url)
+	// the filterer only parses logs, the collection contracts are filtered by the indexers
+	nft, err := bindings.NewNFTFilterer(common.Address{}, client)
 	if err != nil {
-		return nil, err
-	}
-
-	parsedAbi, err := abi.JSON(strings.NewReader(contractABI))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse contract ABI: %w", err)
+		return nil, fmt.Errorf("failed to bind contract: %w", err)
 	}
 
 	return &Worker{
+		chainID:       chainID,
 		client:        client,
 		mq:            mq,
 		tokenQueue:    tokenQueue,
 		transferQueue: transferQueue,
+		approvalQueue: approvalQueue,
 		tokenRepo:     tokenRepo,
 		transferRepo:  transferRepo,
-		parsedABI:     &parsedAbi,
+		approvalRepo:  approvalRepo,
+		chainTxRepo:   chainTxRepo,
+		cursorRepo:    cursorRepo,
+		roleRepo:      roleRepo,
+		finality:      finality,
+		retry:         retry,
+		replacer:      replacer,
+		nft:           nft,
 	}, nil
 }
@@ -0,0 +1,216 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"log/slog"
+	"nft_service/internal/domain"
+	"time"
+)
+
+// StuckTxMonitor periodically looks for transactions without a receipt older than stuckAfter.
+// A stuck transaction is rebroadcast with bumped fees up to maxSpeedUps times, after that it is cancelled.
+func (w *Worker) StuckTxMonitor(ctx context.Context, interval, stuckAfter time.Duration, maxSpeedUps int) {
+	l := slog.Default()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.checkStuckTransactions(stuckAfter, maxSpeedUps)
+		case <-ctx.Done():
+			l.Info("stuck transaction monitor stopped")
+			return
+		}
+	}
+}
+
+func (w *Worker) checkStuckTransactions(stuckAfter time.Duration, maxSpeedUps int) {
+	l := slog.Default()
+
+	stuck, err := w.chainTxRepo.ListStuck(w.chainID, time.Now().Add(-stuckAfter), 100)
+	if err != nil {
+		l.Error("failed to list stuck transactions", slog.Any("error", err))
+		return
+	}
+
+	for _, tx := range stuck {
+		if err := w.handleStuckTransaction(tx, maxSpeedUps); err != nil {
+			l.Error("failed to handle stuck transaction",
+				slog.String("tx_hash", tx.TxHash),
+				slog.Uint64("nonce", tx.Nonce),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+func (w *Worker) handleStuckTransaction(tx *domain.ChainTransaction, maxSpeedUps int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := w.client.TransactionReceipt(ctx, common.HexToHash(tx.TxHash))
+	if err == nil {
+		return w.chainTxRepo.UpdateStatus(domain.ChainTxStatusMined, tx.TxHash)
+	}
+	// only a missing receipt means the transaction is not mined, it is checked again on the next tick otherwise
+	if !errors.Is(err, ethereum.NotFound) {
+		return fmt.Errorf("failed to get receipt: %w", err)
+	}
+
+	confirmedNonce, err := w.client.NonceAt(ctx, common.HexToAddress(tx.FromAddress), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get confirmed nonce: %w", err)
+	}
+
+	if confirmedNonce > tx.Nonce {
+		return w.resolveUsedNonce(ctx, tx)
+	}
+
+	var replacement *domain.ChainTransaction
+	if tx.IsCancel || tx.SpeedUps < maxSpeedUps {
+		replacement, err = w.replacer.SpeedUp(tx)
+	} else {
+		replacement, err = w.replacer.Cancel(tx)
+	}
+	if err != nil {
+		return err
+	}
+
+	return w.trackReplacement(tx.TxHash, replacement)
+}
+
+// resolveUsedNonce handles a transaction whose nonce was consumed by another transaction with the same nonce:
+// the row is moved to whichever of them was mined. Nothing is changed when a receipt can not be fetched.
+func (w *Worker) resolveUsedNonce(ctx context.Context, tx *domain.ChainTransaction) error {
+	siblings, err := w.chainTxRepo.ListByNonce(w.chainID, tx.FromAddress, tx.Nonce)
+	if err != nil {
+		return err
+	}
+
+	var mined *domain.ChainTransaction
+	for _, sibling := range siblings {
+		if sibling.TxHash == tx.TxHash {
+			continue
+		}
+		_, err := w.client.TransactionReceipt(ctx, common.HexToHash(sibling.TxHash))
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get receipt of %s: %w", sibling.TxHash, err)
+		}
+		mined = sibling
+		break
+	}
+
+	if err := w.chainTxRepo.UpdateStatus(domain.ChainTxStatusDropped, tx.TxHash); err != nil {
+		return err
+	}
+
+	if mined != nil {
+		if err := w.chainTxRepo.UpdateStatus(domain.ChainTxStatusMined, mined.TxHash); err != nil {
+			return err
+		}
+		return w.trackReplacement(tx.TxHash, mined)
+	}
+
+	// the nonce was consumed by a transaction sent outside the service
+	switch tx.Kind {
+	case domain.ChainTxKindMint:
+		return w.tokenRepo.UpdateStatus(domain.TokenStatusFailed, tx.TxHash)
+	case domain.ChainTxKindTransfer:
+		return w.transferRepo.UpdateStatus(domain.TransferStatusFailed, tx.TxHash)
+	case domain.ChainTxKindApproval:
+		return w.approvalRepo.UpdateStatus(domain.ApprovalStatusFailed, tx.TxHash)
+	}
+
+	return nil
+}
+
+// trackReplacement moves the nfts/transfers/approvals row to the replacement transaction and queues it for the receipt workers
+func (w *Worker) trackReplacement(oldTxHash string, replacement *domain.ChainTransaction) error {
+	var queueName string
+
+	switch replacement.Kind {
+	case domain.ChainTxKindMint:
+		if err := w.tokenRepo.ReplaceTxHash(oldTxHash, replacement.TxHash); err != nil {
+			return err
+		}
+		queueName = w.tokenQueue
+	case domain.ChainTxKindTransfer:
+		if err := w.transferRepo.ReplaceTxHash(oldTxHash, replacement.TxHash); err != nil {
+			return err
+		}
+		queueName = w.transferQueue
+	case domain.ChainTxKindApproval:
+		if err := w.approvalRepo.ReplaceTxHash(oldTxHash, replacement.TxHash); err != nil {
+			return err
+		}
+		queueName = w.approvalQueue
+	case domain.ChainTxKindRole:
+		// role members follow the RoleGranted and RoleRevoked events, there is no row to move
+		return nil
+	case domain.ChainTxKindNonceFill:
+		// a no-op filling a nonce gap has no row
+		return nil
+	default:
+		return fmt.Errorf("unknown chain transaction kind %q", replacement.Kind)
+	}
+
+	var (
+		entityID  int
+		requestID string
+	)
+	if replacement.Intent != nil {
+		entityID, requestID = replacement.Intent.Row()
+	}
+
+	envelope, err := domain.NewEnvelope(replacement.Kind, entityID, replacement.TxHash, w.chainID, requestID)
+	if err != nil {
+		return err
+	}
+	queueBody, err := envelope.Encode()
+	if err != nil {
+		return err
+	}
+
+	return w.mq.Publish(queueName, queueBody, nil)
+}
+
+// isSuperseded reports whether the transaction was replaced or dropped, so its receipt will never appear
+// and the replacement is tracked by its own message
+func (w *Worker) isSuperseded(txHash string) bool {
+	tx, err := w.chainTxRepo.GetByHash(txHash)
+	if err != nil || tx == nil {
+		return false
+	}
+	return tx.Status == domain.ChainTxStatusReplaced || tx.Status == domain.ChainTxStatusDropped
+}
+
+// markMined records that the transaction got a receipt and returns its record, nil when it is unknown
+func (w *Worker) markMined(txHash string) *domain.ChainTransaction {
+	l := slog.Default()
+
+	tx, err := w.chainTxRepo.GetByHash(txHash)
+	if err != nil {
+		l.Error("failed to get chain transaction", slog.String("tx_hash", txHash), slog.Any("error", err))
+		return nil
+	}
+	if tx == nil {
+		return nil
+	}
+
+	// a mined transaction may still be waiting for the broadcaster to mark it
+	if tx.Status == domain.ChainTxStatusPending || tx.Status == domain.ChainTxStatusSigned {
+		if err := w.chainTxRepo.UpdateStatus(domain.ChainTxStatusMined, txHash); err != nil {
+			l.Error("failed to update chain transaction status", slog.String("tx_hash", txHash), slog.Any("error", err))
+		}
+	}
+
+	return tx
+}
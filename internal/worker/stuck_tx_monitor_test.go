@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nft_service/internal/domain"
+	"nft_service/internal/persistence/mocks"
+	"testing"
+)
+
+func TestTrackReplacement(t *testing.T) {
+	w := newTestWorker(t, newFakeEthService(100))
+	tokens := w.tokenRepo.(*mocks.MockTokenRepository)
+
+	oldTxHash, newTxHash := common.HexToHash("0x01").Hex(), common.HexToHash("0x02").Hex()
+	require.NoError(t, tokens.CreateToken(&domain.Token{ID: 5, TxHash: oldTxHash, UniqueHash: "hash"}))
+
+	// the stored intent keeps the request id next to the token, the token does not store it
+	intent := &domain.TxIntent{Token: &domain.Token{ID: 5}, RequestID: "request-1"}
+
+	replacement := &domain.ChainTransaction{Kind: domain.ChainTxKindMint, TxHash: newTxHash, Intent: intent}
+	require.NoError(t, w.trackReplacement(oldTxHash, replacement))
+
+	assert.Equal(t, domain.TokenStatusPending, tokens.Token(newTxHash).Status)
+
+	msgs, err := w.mq.Peek("token_queue", 10)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	envelope, err := domain.DecodeEnvelope(msgs[0].Body, domain.MessageTypeMintSent)
+	require.NoError(t, err)
+	assert.Equal(t, 5, envelope.EntityID)
+	assert.Equal(t, "request-1", envelope.RequestID)
+	assert.Equal(t, newTxHash, envelope.TxHash)
+}
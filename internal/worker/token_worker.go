@@ -1,77 +1,73 @@
 package worker
 
 import (
-	"context"
-	"errors"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/rabbitmq/amqp091-go"
+	"github.com/ethereum/go-ethereum/core/types"
 	"log/slog"
-	"math/big"
-	"strings"
-	"time"
+	"nft_service/internal/domain"
 )
 
 func (w *Worker) TokenUpdater() error {
-	l := slog.Default()
-	msgs, err := w.mq.Consume(w.tokenQueue.Name)
-	if err != nil {
-		return errors.New("failed to consume token update message")
-	}
-
-	maxWorkers := 10
-	semaphore := make(chan struct{}, maxWorkers)
-
-	for msg := range msgs {
-		semaphore <- struct{}{}
-
-		go func(msg amqp091.Delivery) {
-			defer func() { <-semaphore }()
-
-			txHash := strings.Trim(string(msg.Body), "\"")
+	return w.consumeReceipts(w.tokenRows())
+}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 55*time.Second)
-			defer cancel()
+// tokenRows are the nfts rows tracked by the token queue
+func (w *Worker) tokenRows() receiptRows {
+	return receiptRows{
+		name:           "token",
+		queue:          w.tokenQueue,
+		messageType:    domain.MessageTypeMintSent,
+		markConfirming: w.tokenRepo.MarkConfirming,
+		rollback:       w.tokenRepo.RollbackToPending,
+		markUnknown: func(txHash string) error {
+			return w.tokenRepo.UpdateStatus(domain.TokenStatusUnknown, txHash)
+		},
+		settle: w.settleToken,
+	}
+}
 
-			receipt, err := w.client.TransactionReceipt(ctx, common.HexToHash(txHash))
-			if err != nil {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					time.Sleep(5 * time.Second)
-					msg.Nack(false, true)
-					return
-				}
-			}
+// settleToken records the final receipt of a mint. The token id is taken from the Transfer event from the zero
+// address emitted by the collection contract the mint was sent to, other contracts may emit Transfer events in
+// the same transaction.
+func (w *Worker) settleToken(txHash string, receipt *types.Receipt, chainTx *domain.ChainTransaction) error {
+	// the contract of the mint is the recipient of its stored transaction
+	if chainTx == nil {
+		return fmt.Errorf("chain transaction %s is not known", txHash)
+	}
 
-			var tokenID string
-			for _, log := range receipt.Logs {
-				if log.Topics[0].Hex() == w.parsedABI.Events["Transfer"].ID.Hex() {
-					var transferEvent struct {
-						From    common.Address
-						To      common.Address
-						TokenID *big.Int
-					}
-					if err := w.parsedABI.UnpackIntoInterface(&transferEvent, "Transfer", log.Data); err != nil {
-						l.Error("failed to unpack transfer event", slog.Any("error", err))
-						return
-					}
-					tokenID = new(big.Int).SetBytes(log.Topics[3].Bytes()).String()
-					break
-				}
-			}
+	// failed and cancelled mints are paid for too
+	usage, err := w.gasUsage(receipt)
+	if err != nil {
+		return err
+	}
+	if err := w.tokenRepo.UpdateGas(txHash, usage); err != nil {
+		return err
+	}
 
-			if err := w.tokenRepo.UpdateTokenID(tokenID, txHash); err != nil {
-				l.Error("failed to update token", slog.Any("error", err))
-				msg.Nack(false, true)
-				return
-			}
+	switch {
+	case chainTx.IsCancel:
+		return w.tokenRepo.UpdateStatus(domain.TokenStatusCancelled, txHash)
+	case receipt.Status != types.ReceiptStatusSuccessful:
+		return w.tokenRepo.UpdateStatus(domain.TokenStatusFailed, txHash)
+	}
 
-			if err := msg.Ack(false); err != nil {
-				l.Error("failed to ack message", slog.Any("error", err))
-			}
-		}(msg)
+	contractAddress := common.HexToAddress(chainTx.ToAddress)
+	for _, log := range receipt.Logs {
+		if log.Address != contractAddress {
+			continue
+		}
+		// logs of other events do not parse as Transfer
+		event, err := w.nft.ParseTransfer(*log)
+		if err != nil || event.From != (common.Address{}) {
+			continue
+		}
+		return w.tokenRepo.UpdateTokenID(event.TokenId.String(), txHash)
 	}
 
-	return nil
+	slog.Default().Error("mint transaction has no mint event of the collection",
+		slog.String("tx_hash", txHash),
+		slog.String("contract", chainTx.ToAddress),
+	)
+	return w.tokenRepo.UpdateStatus(domain.TokenStatusFailed, txHash)
 }
@@ -0,0 +1,251 @@
+package worker
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http/httptest"
+	"nft_service/infrastructure/blockchain"
+	"nft_service/infrastructure/messaging"
+	"nft_service/internal/contract"
+	"nft_service/internal/contract/bindings"
+	"nft_service/internal/domain"
+	"nft_service/internal/persistence/mocks"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	testCollection = common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	testWallet     = common.HexToAddress("0xC92f65c05ccdeF650fe1fdeC0221E5f993ea8956")
+	testOwner      = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+)
+
+// fakeEthService serves the blocks and receipts the worker reads from the node
+type fakeEthService struct {
+	head     uint64
+	headers  map[uint64]*types.Header
+	receipts map[common.Hash]*types.Receipt
+	mu       sync.Mutex
+}
+
+func (s *fakeEthService) BlockNumber() hexutil.Uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return hexutil.Uint64(s.head)
+}
+
+func (s *fakeEthService) GetBlockByNumber(number rpc.BlockNumber, _ bool) (*types.Header, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.headers[uint64(number.Int64())], nil
+}
+
+func (s *fakeEthService) GetTransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.receipts[hash], nil
+}
+
+// mine adds the block with the receipt and returns the receipt
+func (s *fakeEthService) mine(number uint64, txHash common.Hash, logs ...*types.Log) *types.Receipt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	header := &types.Header{
+		Number:     new(big.Int).SetUint64(number),
+		Time:       1_700_000_000 + number*12,
+		Difficulty: big.NewInt(0),
+		Extra:      []byte{byte(len(s.receipts))}, // a reorged block has a different hash
+	}
+	s.headers[number] = header
+
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		Logs:              append([]*types.Log{}, logs...),
+		TxHash:            txHash,
+		GasUsed:           120_000,
+		EffectiveGasPrice: big.NewInt(2_000_000_000),
+		BlockHash:         header.Hash(),
+		BlockNumber:       header.Number,
+	}
+	s.receipts[txHash] = receipt
+
+	return receipt
+}
+
+// reorg replaces the block with a different one, the receipts mined in it keep the hash of the old block
+func (s *fakeEthService) reorg(number uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	header := *s.headers[number]
+	header.Extra = append([]byte{0xff}, header.Extra...)
+	s.headers[number] = &header
+}
+
+func newFakeEthService(head uint64) *fakeEthService {
+	return &fakeEthService{
+		head:     head,
+		headers:  make(map[uint64]*types.Header),
+		receipts: make(map[common.Hash]*types.Receipt),
+	}
+}
+
+// fakeChainTxRepo holds the stored transactions by hash, the other methods are not used by the receipt workers
+type fakeChainTxRepo struct {
+	domain.ChainTransactionRepository
+	txs map[string]*domain.ChainTransaction
+	mu  sync.Mutex
+}
+
+func (r *fakeChainTxRepo) GetByHash(txHash string) (*domain.ChainTransaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.txs[txHash], nil
+}
+
+func (r *fakeChainTxRepo) UpdateStatus(status, txHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tx, ok := r.txs[txHash]; ok {
+		tx.Status = status
+	}
+	return nil
+}
+
+func newTestWorker(t *testing.T, eth *fakeEthService) *Worker {
+	t.Helper()
+
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", eth))
+	node := httptest.NewServer(server)
+	t.Cleanup(node.Close)
+
+	client, err := blockchain.NewClient([]string{node.URL}, 5)
+	require.NoError(t, err)
+
+	nft, err := bindings.NewNFTFilterer(common.Address{}, client)
+	require.NoError(t, err)
+
+	retry := messaging.RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 20 * time.Millisecond}
+
+	mq := messaging.NewMemory()
+	t.Cleanup(mq.Close)
+	require.NoError(t, mq.Declare("token_queue", retry))
+
+	return &Worker{
+		chainID:     11155111,
+		client:      client,
+		mq:          mq,
+		tokenQueue:  "token_queue",
+		tokenRepo:   mocks.NewMockTokenRepository(),
+		chainTxRepo: &fakeChainTxRepo{txs: make(map[string]*domain.ChainTransaction)},
+		finality:    blockchain.Finality{Depth: 2},
+		retry:       retry,
+		nft:         nft,
+	}
+}
+
+// transferLog is a Transfer event of the contract
+func transferLog(address, from, to common.Address, tokenID int64) *types.Log {
+	return &types.Log{
+		Address: address,
+		Topics: []common.Hash{
+			contract.TransferTopic,
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+			common.BigToHash(big.NewInt(tokenID)),
+		},
+	}
+}
+
+func TestSettleToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		logs     []*types.Log
+		failed   bool
+		cancel   bool
+		status   string
+		tokenID  string
+		noRecord bool
+	}{
+		{
+			name:    "mint event of the collection",
+			logs:    []*types.Log{transferLog(testCollection, common.Address{}, testOwner, 7)},
+			status:  domain.TokenStatusMinted,
+			tokenID: "7",
+		},
+		{
+			name: "mint event of another contract is skipped",
+			logs: []*types.Log{
+				transferLog(common.HexToAddress("0x1"), common.Address{}, testOwner, 99),
+				transferLog(testCollection, common.Address{}, testOwner, 7),
+			},
+			status:  domain.TokenStatusMinted,
+			tokenID: "7",
+		},
+		{
+			name:   "transfer of an existing token is not a mint",
+			logs:   []*types.Log{transferLog(testCollection, testWallet, testOwner, 3)},
+			status: domain.TokenStatusFailed,
+		},
+		{
+			name:   "no mint event",
+			status: domain.TokenStatusFailed,
+		},
+		{
+			name:   "reverted mint",
+			logs:   []*types.Log{transferLog(testCollection, common.Address{}, testOwner, 7)},
+			failed: true,
+			status: domain.TokenStatusFailed,
+		},
+		{
+			name:   "cancelled mint",
+			cancel: true,
+			status: domain.TokenStatusCancelled,
+		},
+		{
+			name:     "unknown transaction",
+			noRecord: true,
+			status:   domain.TokenStatusPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eth := newFakeEthService(100)
+			w := newTestWorker(t, eth)
+			tokens := w.tokenRepo.(*mocks.MockTokenRepository)
+
+			txHash := common.HexToHash("0xabc")
+			require.NoError(t, tokens.CreateToken(&domain.Token{ID: 1, TxHash: txHash.Hex(), UniqueHash: "hash"}))
+
+			receipt := eth.mine(90, txHash, tt.logs...)
+			if tt.failed {
+				receipt.Status = types.ReceiptStatusFailed
+			}
+
+			var chainTx *domain.ChainTransaction
+			if !tt.noRecord {
+				chainTx = &domain.ChainTransaction{TxHash: txHash.Hex(), ToAddress: testCollection.Hex(), IsCancel: tt.cancel}
+			}
+
+			err := w.settleToken(txHash.Hex(), receipt, chainTx)
+			if tt.noRecord {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			token := tokens.Token(txHash.Hex())
+			assert.Equal(t, tt.status, token.Status)
+			assert.Equal(t, tt.tokenID, token.TokenID)
+		})
+	}
+}
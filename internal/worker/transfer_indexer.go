@@ -0,0 +1,220 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"log/slog"
+	"math/big"
+	"nft_service/infrastructure/blockchain"
+	"nft_service/internal/contract"
+	"nft_service/internal/domain"
+	"time"
+)
+
+const transferIndexerCursor = "transfer_events"
+
+type IndexerConfig struct {
+	CollectionID    int
+	ContractAddress common.Address
+	StartBlock      *uint64 // first block to index when there is no cursor yet, nil means the current head
+	BlockRange      uint64
+	PollInterval    time.Duration
+}
+
+// TransferIndexer follows the final Transfer events of the collection contract from the stored cursor,
+// keeps nfts.owner in sync and records transfers made outside the service. Blocks are polled with FilterLogs, a websocket
+// subscription, when a websocket provider is configured, only triggers the next poll early.
+func (w *Worker) TransferIndexer(ctx context.Context, cfg IndexerConfig) {
+	var (
+		l      = slog.Default().With(slog.Int("collection_id", cfg.CollectionID))
+		events = make(chan types.Log, 64)
+		sub    ethereum.Subscription
+		subErr <-chan error
+	)
+
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	subscribe := func() {
+		var err error
+		sub, err = w.client.SubscribeFilterLogs(ctx, w.eventFilter(cfg, w.transferTopics(), nil, nil), events)
+		switch {
+		case err == nil:
+			subErr = sub.Err()
+		case errors.Is(err, blockchain.ErrNoSubscriptionProvider):
+		default:
+			l.Error("failed to subscribe to transfer events", slog.Any("error", err))
+		}
+	}
+
+	subscribe()
+	defer func() {
+		if sub != nil {
+			sub.Unsubscribe()
+		}
+	}()
+
+	for {
+		if err := w.syncTransfers(ctx, cfg); err != nil {
+			l.Error("failed to index transfer events", slog.Any("error", err))
+		}
+
+		select {
+		case <-ticker.C:
+			if sub == nil {
+				subscribe()
+			}
+		case <-events:
+			// drain the burst of events, they are all picked up by the next sync
+			for len(events) > 0 {
+				<-events
+			}
+		case err := <-subErr:
+			l.Warn("transfer events subscription dropped", slog.Any("error", err))
+			sub, subErr = nil, nil
+		case <-ctx.Done():
+			l.Info("transfer indexer stopped")
+			return
+		}
+	}
+}
+
+func (w *Worker) syncTransfers(ctx context.Context, cfg IndexerConfig) error {
+	cursor := fmt.Sprintf("%s:%d", transferIndexerCursor, cfg.CollectionID)
+	return w.syncEvents(ctx, cfg, cursor, w.transferTopics(), func(log types.Log) error {
+		return w.applyTransferLog(cfg.CollectionID, log)
+	})
+}
+
+// syncEvents applies the contract logs with one of the topics between the cursor and the last final block
+// in ranges of cfg.BlockRange, moving the cursor after every range
+func (w *Worker) syncEvents(ctx context.Context, cfg IndexerConfig, cursor string, topics []common.Hash,
+	apply func(log types.Log) error,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	head, err := w.client.SafeHead(ctx, w.finality)
+	if err != nil {
+		return err
+	}
+
+	from, err := w.nextIndexedBlock(cfg, cursor, head)
+	if err != nil {
+		return err
+	}
+
+	for from <= head {
+		to := from + cfg.BlockRange - 1
+		if to > head {
+			to = head
+		}
+
+		logs, err := w.client.FilterLogs(ctx, w.eventFilter(cfg, topics, new(big.Int).SetUint64(from), new(big.Int).SetUint64(to)))
+		if err != nil {
+			return fmt.Errorf("failed to filter %s logs %d-%d: %w", cursor, from, to, err)
+		}
+
+		for _, log := range logs {
+			if err := apply(log); err != nil {
+				return err
+			}
+		}
+
+		if err := w.cursorRepo.SaveCursor(cursor, to); err != nil {
+			return err
+		}
+
+		from = to + 1
+	}
+
+	return nil
+}
+
+// nextIndexedBlock returns the first block that has not been indexed yet by the cursor
+func (w *Worker) nextIndexedBlock(cfg IndexerConfig, cursorName string, head uint64) (uint64, error) {
+	cursor, found, err := w.cursorRepo.GetCursor(cursorName)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case found:
+		return cursor + 1, nil
+	case cfg.StartBlock != nil:
+		return *cfg.StartBlock, nil
+	default:
+		return head, nil
+	}
+}
+
+func (w *Worker) transferTopics() []common.Hash {
+	return []common.Hash{contract.TransferTopic}
+}
+
+// eventFilter matches the contract logs whose event is one of the topics
+func (w *Worker) eventFilter(cfg IndexerConfig, topics []common.Hash, from, to *big.Int) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: from,
+		ToBlock:   to,
+		Addresses: []common.Address{cfg.ContractAddress},
+		Topics:    [][]common.Hash{topics},
+	}
+}
+
+// applyTransferLog moves the token of the collection to its new owner and records the transfer
+// if the service did not make it
+func (w *Worker) applyTransferLog(collectionID int, log types.Log) error {
+	if log.Removed {
+		return nil
+	}
+
+	event, err := w.nft.ParseTransfer(log)
+	if err != nil {
+		slog.Default().Warn("skipping unparsable transfer log", slog.String("tx_hash", log.TxHash.Hex()), slog.Any("error", err))
+		return nil
+	}
+
+	var (
+		from    = event.From
+		to      = event.To
+		tokenID = event.TokenId.String()
+	)
+
+	if err := w.tokenRepo.UpdateOwner(collectionID, tokenID, to.Hex()); err != nil {
+		return err
+	}
+
+	// mints are tracked by the nfts table
+	if from == (common.Address{}) {
+		return nil
+	}
+
+	inserted, err := w.transferRepo.CreateIfMissing(&domain.Transfer{
+		CollectionID: collectionID,
+		ChainID:      w.chainID,
+		FromAddress:  from.Hex(),
+		ToAddress:    to.Hex(),
+		TokenID:      tokenID,
+		TxHash:       log.TxHash.Hex(),
+		Status:       domain.TransferStatusSuccess,
+	})
+	if err != nil {
+		return err
+	}
+
+	if inserted {
+		slog.Default().Info("external transfer indexed",
+			slog.Int("collection_id", collectionID),
+			slog.String("tx_hash", log.TxHash.Hex()),
+			slog.String("token_id", tokenID),
+			slog.Uint64("block_number", log.BlockNumber),
+		)
+	}
+
+	return nil
+}
@@ -1,66 +1,43 @@
 package worker
 
 import (
-	"context"
-	"errors"
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/rabbitmq/amqp091-go"
-	"log/slog"
-	"strings"
-	"time"
+	"github.com/ethereum/go-ethereum/core/types"
+	"nft_service/internal/domain"
 )
 
 func (w *Worker) TransferStatusUpdater() error {
-	l := slog.Default()
-	msgs, err := w.mq.Consume(w.transferQueue.Name)
+	return w.consumeReceipts(receiptRows{
+		name:           "transfer",
+		queue:          w.transferQueue,
+		messageType:    domain.MessageTypeTransferSent,
+		markConfirming: w.transferRepo.MarkConfirming,
+		rollback:       w.transferRepo.RollbackToPending,
+		markUnknown: func(txHash string) error {
+			return w.transferRepo.UpdateStatus(domain.TransferStatusUnknown, txHash)
+		},
+		settle: w.settleTransfer,
+	})
+}
+
+func (w *Worker) settleTransfer(txHash string, receipt *types.Receipt, chainTx *domain.ChainTransaction) error {
+	// failed and cancelled transfers are paid for too
+	usage, err := w.gasUsage(receipt)
 	if err != nil {
-		return errors.New("failed to consume transfer update message")
+		return err
+	}
+	if err := w.transferRepo.UpdateGas(txHash, usage); err != nil {
+		return err
 	}
 
-	maxWorkers := 10
-	semaphore := make(chan struct{}, maxWorkers)
-
-	for msg := range msgs {
-		semaphore <- struct{}{}
-
-		go func(msg amqp091.Delivery) {
-			defer func() { <-semaphore }()
-
-			txHash := strings.Trim(string(msg.Body), "\"")
-
-			ctx, cancel := context.WithTimeout(context.Background(), 55*time.Second)
-			defer cancel()
-
-			receipt, err := w.client.TransactionReceipt(ctx, common.HexToHash(txHash))
-			if err != nil {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					time.Sleep(5 * time.Second)
-					msg.Nack(false, true)
-					return
-				}
-			}
-
-			var txStatus string
-			if receipt.Status == 1 {
-				txStatus = "success"
-			} else {
-				txStatus = "failed"
-			}
-
-			if err := w.transferRepo.UpdateStatus(txStatus, txHash); err != nil {
-				l.Error("failed to update transfer status", slog.Any("error", err))
-				msg.Nack(false, true)
-				return
-			}
-
-			if err := msg.Ack(false); err != nil {
-				l.Error("failed to ack message", slog.Any("error", err))
-			}
-		}(msg)
+	var txStatus string
+	switch {
+	case chainTx != nil && chainTx.IsCancel:
+		txStatus = domain.TransferStatusCancelled
+	case receipt.Status == types.ReceiptStatusSuccessful:
+		txStatus = domain.TransferStatusSuccess
+	default:
+		txStatus = domain.TransferStatusFailed
 	}
 
-	return nil
+	return w.transferRepo.UpdateStatus(txStatus, txHash)
 }
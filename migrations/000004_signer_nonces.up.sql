@@ -0,0 +1,10 @@
+BEGIN;
+
+CREATE TABLE signer_nonces
+(
+    address    VARCHAR(42) PRIMARY KEY, -- ETH address of the signer
+    nonce      BIGINT      NOT NULL,    -- last nonce handed out for this signer
+    updated_at TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE nfts DROP COLUMN status;
+DROP TABLE IF EXISTS chain_transactions;
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TABLE chain_transactions
+(
+    id           SERIAL PRIMARY KEY,
+    tx_hash      VARCHAR(66)    NOT NULL UNIQUE,
+    kind         VARCHAR(16)    NOT NULL,                -- operation of the nfts/transfers row: mint | transfer
+    is_cancel    BOOLEAN        NOT NULL DEFAULT FALSE,  -- zero-value self-transfer replacing a stuck transaction
+    from_address VARCHAR(42)    NOT NULL,
+    to_address   VARCHAR(42)    NOT NULL,
+    nonce        BIGINT         NOT NULL,
+    data         BYTEA,
+    gas_limit    BIGINT         NOT NULL,
+    gas_tip_cap  NUMERIC(78, 0) NOT NULL,                -- wei
+    gas_fee_cap  NUMERIC(78, 0) NOT NULL,                -- wei
+    status       VARCHAR(10)    NOT NULL DEFAULT 'pending',
+    replaced_by  VARCHAR(66),                            -- hash of the speed-up or cancel transaction
+    speed_ups    INT            NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP      NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX index_chain_transactions_status ON chain_transactions (status, updated_at);
+CREATE INDEX index_chain_transactions_nonce ON chain_transactions (from_address, nonce);
+
+ALTER TABLE nfts ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'pending';
+UPDATE nfts SET status = 'minted' WHERE token_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS index_transfers_token_id;
+DROP INDEX IF EXISTS index_nfts_token_id;
+DROP TABLE IF EXISTS indexer_cursors;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE indexer_cursors
+(
+    name         VARCHAR(64) PRIMARY KEY, -- indexer name
+    block_number BIGINT      NOT NULL,    -- last fully processed block
+    updated_at   TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX index_nfts_token_id ON nfts (token_id);
+CREATE INDEX index_transfers_token_id ON transfers (token_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE transfers DROP COLUMN block_hash;
+ALTER TABLE transfers DROP COLUMN block_number;
+
+ALTER TABLE nfts DROP COLUMN block_hash;
+ALTER TABLE nfts DROP COLUMN block_number;
+
+COMMIT;
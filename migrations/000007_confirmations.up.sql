@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE nfts ADD COLUMN block_number BIGINT;
+ALTER TABLE nfts ADD COLUMN block_hash VARCHAR(66);
+
+ALTER TABLE transfers ADD COLUMN block_number BIGINT;
+ALTER TABLE transfers ADD COLUMN block_hash VARCHAR(66);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS mint_batch_items;
+DROP TABLE IF EXISTS mint_batches;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE mint_batches
+(
+    id         SERIAL PRIMARY KEY,
+    status     VARCHAR(12) NOT NULL DEFAULT 'processing', -- processing, completed
+    total      INT         NOT NULL,                      -- number of items in the batch
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE mint_batch_items
+(
+    id          SERIAL PRIMARY KEY,
+    batch_id    INT           NOT NULL REFERENCES mint_batches (id) ON DELETE CASCADE,
+    position    INT           NOT NULL,                  -- index of the item in the request
+    owner       VARCHAR(42)   NOT NULL,
+    media_url   VARCHAR(2048) NOT NULL,
+    unique_hash VARCHAR(20)   NOT NULL UNIQUE,
+    tx_hash     VARCHAR(66),                             -- set once the mint transaction is broadcast
+    status      VARCHAR(10)   NOT NULL DEFAULT 'queued', -- queued, sent, failed
+    error       TEXT,                                    -- reason the item was not broadcast
+    updated_at  TIMESTAMP     NOT NULL DEFAULT NOW(),
+    UNIQUE (batch_id, position)
+);
+
+COMMIT;
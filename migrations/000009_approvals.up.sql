@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE approvals
+(
+    id           SERIAL PRIMARY KEY,
+    kind         VARCHAR(10)  NOT NULL,                   -- token (approve) or operator (setApprovalForAll)
+    token_id     NUMERIC(78, 0),                          -- approved token, NULL for operator approvals
+    operator     VARCHAR(42)  NOT NULL,                   -- approved address, zero address revokes a token approval
+    approved     BOOLEAN      NOT NULL,
+    tx_hash      VARCHAR(66)  NOT NULL UNIQUE,
+    status       VARCHAR(10)  NOT NULL DEFAULT 'pending', -- pending, confirming, success, failed, cancelled
+    block_number BIGINT,
+    block_hash   VARCHAR(66),
+    created_at   TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE role_members
+(
+    role         VARCHAR(66) NOT NULL, -- bytes32 role id
+    account      VARCHAR(42) NOT NULL, -- member address
+    granted_by   VARCHAR(42) NOT NULL, -- sender of the RoleGranted event
+    tx_hash      VARCHAR(66) NOT NULL, -- transaction of the RoleGranted event
+    block_number BIGINT      NOT NULL,
+    updated_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (role, account)
+);
+
+COMMIT;
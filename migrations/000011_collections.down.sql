@@ -0,0 +1,17 @@
+BEGIN;
+
+-- the default collection is the first one seeded
+UPDATE indexer_cursors SET name = 'transfer_events'
+WHERE name = 'transfer_events:' || (SELECT MIN(id) FROM collections);
+DELETE FROM indexer_cursors WHERE name LIKE 'transfer_events:%';
+
+DROP INDEX IF EXISTS index_transfers_collection_id;
+DROP INDEX IF EXISTS index_nfts_collection_id_token_id;
+
+ALTER TABLE mint_batches DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE transfers DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE nfts DROP COLUMN IF EXISTS collection_id;
+
+DROP TABLE IF EXISTS collections;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+CREATE TABLE collections
+(
+    id         SERIAL PRIMARY KEY,
+    address    VARCHAR(42)  NOT NULL,            -- contract address
+    chain_id   BIGINT       NOT NULL,
+    abi_ref    TEXT         NOT NULL DEFAULT '', -- path of the ABI file checked against the bindings, empty to skip
+    name       VARCHAR(255) NOT NULL,            -- display name
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    UNIQUE (chain_id, address)
+);
+
+-- NULL for rows created before collections, they are moved to the default collection when it is seeded
+ALTER TABLE nfts ADD COLUMN collection_id INT REFERENCES collections (id);
+ALTER TABLE transfers ADD COLUMN collection_id INT REFERENCES collections (id);
+ALTER TABLE mint_batches ADD COLUMN collection_id INT REFERENCES collections (id);
+
+CREATE INDEX index_nfts_collection_id_token_id ON nfts (collection_id, token_id);
+CREATE INDEX index_transfers_collection_id ON transfers (collection_id);
+
+-- the transfer indexer cursor is kept per collection, the cursor of the env-configured contract is renamed
+-- when it is seeded as the default collection
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+DROP INDEX IF EXISTS index_transfers_chain_id;
+DROP INDEX IF EXISTS index_nfts_chain_id;
+
+DROP INDEX IF EXISTS index_chain_transactions_nonce;
+DROP INDEX IF EXISTS index_chain_transactions_status;
+CREATE INDEX index_chain_transactions_status ON chain_transactions (status, updated_at);
+CREATE INDEX index_chain_transactions_nonce ON chain_transactions (from_address, nonce);
+
+-- only the nonces of the chain of the first collection can be kept with the address as the key
+DELETE FROM signer_nonces WHERE chain_id <> COALESCE((SELECT chain_id FROM collections ORDER BY id LIMIT 1), 0);
+ALTER TABLE signer_nonces DROP CONSTRAINT signer_nonces_pkey;
+ALTER TABLE signer_nonces ADD PRIMARY KEY (address);
+ALTER TABLE signer_nonces DROP COLUMN IF EXISTS chain_id;
+
+ALTER TABLE chain_transactions DROP COLUMN IF EXISTS chain_id;
+ALTER TABLE transfers DROP COLUMN IF EXISTS chain_id;
+ALTER TABLE nfts DROP COLUMN IF EXISTS chain_id;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+-- NULL for rows created before chains, they take the chain of the default collection when it is seeded
+ALTER TABLE nfts ADD COLUMN chain_id BIGINT;
+ALTER TABLE transfers ADD COLUMN chain_id BIGINT;
+
+-- 0 for rows created before chains, they are moved to the chain of the default collection when it is seeded
+ALTER TABLE chain_transactions ADD COLUMN chain_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE signer_nonces ADD COLUMN chain_id BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE signer_nonces DROP CONSTRAINT signer_nonces_pkey;
+ALTER TABLE signer_nonces ADD PRIMARY KEY (chain_id, address);
+
+DROP INDEX IF EXISTS index_chain_transactions_status;
+DROP INDEX IF EXISTS index_chain_transactions_nonce;
+CREATE INDEX index_chain_transactions_status ON chain_transactions (chain_id, status, updated_at);
+CREATE INDEX index_chain_transactions_nonce ON chain_transactions (chain_id, from_address, nonce);
+
+CREATE INDEX index_nfts_chain_id ON nfts (chain_id);
+CREATE INDEX index_transfers_chain_id ON transfers (chain_id);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP INDEX IF EXISTS index_nfts_gas_report;
+DROP INDEX IF EXISTS index_transfers_gas_report;
+
+ALTER TABLE nfts DROP COLUMN gas_used;
+ALTER TABLE nfts DROP COLUMN effective_gas_price;
+ALTER TABLE nfts DROP COLUMN fee_wei;
+
+ALTER TABLE transfers DROP COLUMN gas_used;
+ALTER TABLE transfers DROP COLUMN effective_gas_price;
+ALTER TABLE transfers DROP COLUMN fee_wei;
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- set from the receipt once the transaction is final, NULL for pending rows and transfers made outside the service
+ALTER TABLE nfts ADD COLUMN gas_used BIGINT;
+ALTER TABLE nfts ADD COLUMN effective_gas_price NUMERIC(78, 0); -- wei
+ALTER TABLE nfts ADD COLUMN fee_wei NUMERIC(78, 0);
+
+ALTER TABLE transfers ADD COLUMN gas_used BIGINT;
+ALTER TABLE transfers ADD COLUMN effective_gas_price NUMERIC(78, 0); -- wei
+ALTER TABLE transfers ADD COLUMN fee_wei NUMERIC(78, 0);
+
+CREATE INDEX index_nfts_gas_report ON nfts (chain_id, created_at) WHERE fee_wei IS NOT NULL;
+CREATE INDEX index_transfers_gas_report ON transfers (chain_id, created_at) WHERE fee_wei IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+DROP INDEX IF EXISTS index_nfts_chain_transaction_id;
+DROP INDEX IF EXISTS index_transfers_chain_transaction_id;
+DROP INDEX IF EXISTS index_approvals_chain_transaction_id;
+
+ALTER TABLE nfts DROP COLUMN chain_transaction_id;
+ALTER TABLE transfers DROP COLUMN chain_transaction_id;
+ALTER TABLE approvals DROP COLUMN chain_transaction_id;
+
+-- signed transactions that were never broadcast are unknown to the previous version
+DELETE FROM chain_transactions WHERE status = 'signed';
+
+ALTER TABLE chain_transactions DROP COLUMN raw_tx;
+ALTER TABLE chain_transactions DROP COLUMN intent;
+
+COMMIT;
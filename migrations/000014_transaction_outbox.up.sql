@@ -0,0 +1,21 @@
+BEGIN;
+
+-- transactions are stored signed before they are broadcast, so a crash between signing and recording
+-- can not leave a transaction on chain the service does not know about
+ALTER TABLE chain_transactions ADD COLUMN raw_tx BYTEA;   -- signed transaction, NULL for rows recorded before the outbox
+ALTER TABLE chain_transactions ADD COLUMN intent JSONB;   -- rows the transaction was created for
+
+-- the transaction the row is currently tracked by, follows speed-ups and cancels
+ALTER TABLE nfts ADD COLUMN chain_transaction_id INT REFERENCES chain_transactions (id);
+ALTER TABLE transfers ADD COLUMN chain_transaction_id INT REFERENCES chain_transactions (id);
+ALTER TABLE approvals ADD COLUMN chain_transaction_id INT REFERENCES chain_transactions (id);
+
+UPDATE nfts n SET chain_transaction_id = c.id FROM chain_transactions c WHERE c.tx_hash = n.tx_hash;
+UPDATE transfers t SET chain_transaction_id = c.id FROM chain_transactions c WHERE c.tx_hash = t.tx_hash;
+UPDATE approvals a SET chain_transaction_id = c.id FROM chain_transactions c WHERE c.tx_hash = a.tx_hash;
+
+CREATE INDEX index_nfts_chain_transaction_id ON nfts (chain_transaction_id);
+CREATE INDEX index_transfers_chain_transaction_id ON transfers (chain_transaction_id);
+CREATE INDEX index_approvals_chain_transaction_id ON approvals (chain_transaction_id);
+
+COMMIT;
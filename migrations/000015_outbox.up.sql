@@ -0,0 +1,20 @@
+BEGIN;
+
+-- messages for the receipt queues, written in the same transaction as the rows they track
+-- and published to RabbitMQ by the outbox relay
+CREATE TABLE outbox (
+    id                   BIGSERIAL PRIMARY KEY,
+    chain_transaction_id INT NOT NULL REFERENCES chain_transactions (id),
+    chain_id             BIGINT NOT NULL,
+    kind                 VARCHAR(16) NOT NULL, -- kind of the chain transaction, selects the queue
+    payload              BYTEA NOT NULL,
+    attempts             INT NOT NULL DEFAULT 0,
+    last_error           TEXT,
+    created_at           TIMESTAMP NOT NULL DEFAULT NOW(),
+    published_at         TIMESTAMP
+);
+
+CREATE INDEX index_outbox_unpublished ON outbox (id) WHERE published_at IS NULL;
+CREATE INDEX index_outbox_chain_transaction_id ON outbox (chain_transaction_id);
+
+COMMIT;
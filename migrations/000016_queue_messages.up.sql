@@ -0,0 +1,16 @@
+BEGIN;
+
+-- messages of the work queues when QUEUE_BACKEND is postgres, consumers lease them with FOR UPDATE SKIP LOCKED
+CREATE TABLE queue_messages (
+    id           BIGSERIAL PRIMARY KEY,
+    queue        VARCHAR(255) NOT NULL,
+    body         BYTEA NOT NULL,
+    headers      JSONB NOT NULL DEFAULT '{}',
+    available_at TIMESTAMPTZ NOT NULL DEFAULT NOW(), -- later for messages waiting for a retry
+    locked_until TIMESTAMPTZ, -- set while a consumer handles the message
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX index_queue_messages_queue ON queue_messages (queue, id);
+
+COMMIT;
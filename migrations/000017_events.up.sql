@@ -0,0 +1,21 @@
+BEGIN;
+
+-- domain events for other services, written in the same transaction as the change of the row they describe
+-- and published to the events exchange by the event relay
+CREATE TABLE events (
+    id           BIGSERIAL PRIMARY KEY,
+    type         VARCHAR(64) NOT NULL,
+    entity_id    INT NOT NULL, -- id of the nfts or transfers row, the type tells which
+    chain_id     BIGINT NOT NULL,
+    request_id   VARCHAR(255),
+    data         JSONB NOT NULL, -- the row after the change
+    attempts     INT NOT NULL DEFAULT 0,
+    last_error   TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX index_events_unpublished ON events (id) WHERE published_at IS NULL;
+CREATE INDEX index_events_entity ON events (entity_id, type);
+
+COMMIT;
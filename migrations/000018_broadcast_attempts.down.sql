@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE chain_transactions DROP COLUMN IF EXISTS broadcast_attempts;
+
+COMMIT;
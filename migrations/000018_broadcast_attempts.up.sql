@@ -0,0 +1,6 @@
+BEGIN;
+
+-- failed sends of a signed transaction by the broadcaster, it is cancelled after too many of them
+ALTER TABLE chain_transactions ADD COLUMN broadcast_attempts INT NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE events DROP COLUMN IF EXISTS chain_transaction_id;
+
+COMMIT;
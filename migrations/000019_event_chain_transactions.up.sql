@@ -0,0 +1,9 @@
+BEGIN;
+
+-- transaction that created the token or transfer of a requested event, the event is held back until it is broadcast
+-- and deleted with it when the node rejects it
+ALTER TABLE events ADD COLUMN chain_transaction_id INT REFERENCES chain_transactions (id);
+
+CREATE INDEX index_events_chain_transaction_id ON events (chain_transaction_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS parked_at;
+ALTER TABLE outbox DROP COLUMN IF EXISTS locked_until;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- the relay of one instance claims the messages it publishes until locked_until, messages that can not be
+-- published are parked and left out until parked_at is cleared
+ALTER TABLE outbox ADD COLUMN locked_until TIMESTAMP;
+ALTER TABLE outbox ADD COLUMN parked_at TIMESTAMP;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP INDEX IF EXISTS index_nfts_gas_report;
+DROP INDEX IF EXISTS index_transfers_gas_report;
+
+ALTER TABLE nfts DROP COLUMN IF EXISTS mined_at;
+ALTER TABLE transfers DROP COLUMN IF EXISTS mined_at;
+
+CREATE INDEX index_nfts_gas_report ON nfts (chain_id, created_at) WHERE fee_wei IS NOT NULL;
+CREATE INDEX index_transfers_gas_report ON transfers (chain_id, created_at) WHERE fee_wei IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+-- time of the block the final transaction was mined in, the gas report groups the fees by it
+ALTER TABLE nfts ADD COLUMN mined_at TIMESTAMPTZ;
+ALTER TABLE transfers ADD COLUMN mined_at TIMESTAMPTZ;
+
+-- the block time of rows settled before is unknown, they keep being reported at their creation
+UPDATE nfts SET mined_at = created_at WHERE fee_wei IS NOT NULL;
+UPDATE transfers SET mined_at = created_at WHERE fee_wei IS NOT NULL;
+
+DROP INDEX IF EXISTS index_nfts_gas_report;
+DROP INDEX IF EXISTS index_transfers_gas_report;
+
+CREATE INDEX index_nfts_gas_report ON nfts (chain_id, mined_at) WHERE fee_wei IS NOT NULL;
+CREATE INDEX index_transfers_gas_report ON transfers (chain_id, mined_at) WHERE fee_wei IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE signer_nonces
+(
+    chain_id   BIGINT      NOT NULL,
+    address    VARCHAR(42) NOT NULL, -- ETH address of the signer
+    nonce      BIGINT      NOT NULL, -- last nonce handed out for this signer
+    updated_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (chain_id, address)
+);
+
+-- the addresses are stored checksummed like the service writes them
+INSERT INTO signer_nonces (chain_id, address, nonce)
+SELECT chain_id, from_address, MAX(nonce)
+FROM chain_transactions
+GROUP BY chain_id, from_address;
+
+COMMIT;
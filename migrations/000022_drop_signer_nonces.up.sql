@@ -0,0 +1,6 @@
+BEGIN;
+
+-- the next nonce is taken from the stored transactions that are not mined yet
+DROP TABLE IF EXISTS signer_nonces;
+
+COMMIT;
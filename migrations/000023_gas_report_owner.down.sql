@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE nfts DROP COLUMN IF EXISTS minted_to;
+
+ALTER TABLE nfts ALTER COLUMN mined_at TYPE TIMESTAMPTZ USING mined_at AT TIME ZONE 'UTC';
+ALTER TABLE transfers ALTER COLUMN mined_at TYPE TIMESTAMPTZ USING mined_at AT TIME ZONE 'UTC';
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+-- mined_at holds the block time in UTC like the other timestamp columns
+ALTER TABLE nfts ALTER COLUMN mined_at TYPE TIMESTAMP USING mined_at AT TIME ZONE 'UTC';
+ALTER TABLE transfers ALTER COLUMN mined_at TYPE TIMESTAMP USING mined_at AT TIME ZONE 'UTC';
+
+-- address the token was minted to, unlike owner it does not follow the transfers
+ALTER TABLE nfts ADD COLUMN minted_to VARCHAR(42);
+
+-- the stored intents have no token row id, the unique hash identifies the token
+UPDATE nfts n SET minted_to = COALESCE(
+    (SELECT ct.intent -> 'token' ->> 'owner' FROM chain_transactions ct
+     WHERE ct.intent -> 'token' ->> 'unique_hash' = n.unique_hash ORDER BY ct.id LIMIT 1),
+    n.owner);
+
+ALTER TABLE nfts ALTER COLUMN minted_to SET NOT NULL;
+
+COMMIT;
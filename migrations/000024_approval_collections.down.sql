@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS index_approvals_collection_id;
+
+ALTER TABLE approvals DROP COLUMN IF EXISTS collection_id;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- NULL for approvals sent before they were kept per collection, they are moved to the default collection
+-- when it is seeded
+ALTER TABLE approvals ADD COLUMN collection_id INT REFERENCES collections (id);
+
+CREATE INDEX index_approvals_collection_id ON approvals (collection_id);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- the default collection is the first one seeded
+UPDATE indexer_cursors SET name = 'role_events'
+WHERE name = 'role_events:' || (SELECT MIN(id) FROM collections);
+DELETE FROM indexer_cursors WHERE name LIKE 'role_events:%';
+
+DELETE FROM role_members WHERE collection_id <> (SELECT MIN(id) FROM collections);
+
+DROP INDEX IF EXISTS index_role_members_collection_id_role_account;
+ALTER TABLE role_members DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE role_members ADD PRIMARY KEY (role, account);
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- NULL for members indexed before roles were kept per collection, they are moved to the default collection
+-- when it is seeded
+ALTER TABLE role_members ADD COLUMN collection_id INT REFERENCES collections (id);
+
+ALTER TABLE role_members DROP CONSTRAINT role_members_pkey;
+CREATE UNIQUE INDEX index_role_members_collection_id_role_account ON role_members (collection_id, role, account);
+
+-- the role indexer cursor is kept per collection, the cursor of the env-configured contract is renamed
+-- when it is seeded as the default collection
+
+COMMIT;
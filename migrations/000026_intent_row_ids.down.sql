@@ -0,0 +1,5 @@
+BEGIN;
+
+-- the row ids are left in the intents, they are ignored by the versions before
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- the intents stored before they kept the ids of their rows, replacements carry the intent into their messages
+UPDATE chain_transactions c SET intent = jsonb_set(c.intent, '{token,id}', to_jsonb(n.id))
+FROM nfts n WHERE n.chain_transaction_id = c.id AND c.intent ? 'token';
+
+UPDATE chain_transactions c SET intent = jsonb_set(c.intent, '{transfer,id}', to_jsonb(t.id))
+FROM transfers t WHERE t.chain_transaction_id = c.id AND c.intent ? 'transfer';
+
+UPDATE chain_transactions c SET intent = jsonb_set(c.intent, '{approval,id}', to_jsonb(a.id))
+FROM approvals a WHERE a.chain_transaction_id = c.id AND c.intent ? 'approval';
+
+COMMIT;